@@ -0,0 +1,557 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+// fakeRows is an in-memory result set implementing RowsScanner and,
+// when columns are set, ColumnsScanner.
+type fakeRows struct {
+	columns []string
+	data    [][]any
+	pos     int
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.data)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.data[r.pos-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, value := range row {
+		*(dest[i].(*any)) = value
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return nil }
+func (r *fakeRows) Close() error { return nil }
+
+// namedRows adds column names to fakeRows.
+type namedRows struct {
+	fakeRows
+}
+
+func (r *namedRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+type binderUser struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Email string
+}
+
+func TestBinderMapsColumnsByName(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"EMAIL", "created_at", "Name", "id"},
+		data: [][]any{
+			{"a@example.com", "2024-01-01", "Alice", int64(1)},
+			{"b@example.com", "2024-01-02", "Bob", int64(2)},
+		},
+	}}
+
+	var users []binderUser
+	if err := sqlx.NewBinder().BindRows(rows, &users); err != nil {
+		t.Fatalf("BindRows failed: %v", err)
+	}
+
+	expected := []binderUser{
+		{ID: 1, Name: "Alice", Email: "a@example.com"},
+		{ID: 2, Name: "Bob", Email: "b@example.com"},
+	}
+	if len(users) != len(expected) {
+		t.Fatalf("BindRows returned %d users, want %d", len(users), len(expected))
+	}
+	for i := range expected {
+		if users[i] != expected[i] {
+			t.Errorf("users[%d] = %+v, want %+v", i, users[i], expected[i])
+		}
+	}
+}
+
+func TestBinderStrictColumns(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"id", "unknown"},
+		data:    [][]any{{int64(1), "x"}},
+	}}
+	rows.Next()
+
+	var user binderUser
+	err := sqlx.NewBinder().WithStrictColumns(true).BindRow(rows, &user)
+	if !errors.Is(err, sqlx.ErrInvalidColumnName) {
+		t.Errorf("BindRow error = %v, want ErrInvalidColumnName", err)
+	}
+
+	if err := sqlx.NewBinder().BindRow(rows, &user); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+	if user.ID != 1 {
+		t.Errorf("user.ID = %d, want 1", user.ID)
+	}
+}
+
+func TestBinderPositionalFallback(t *testing.T) {
+	rows := &fakeRows{data: [][]any{{int64(3), "Carol", "c@example.com"}}}
+	rows.Next()
+
+	var user binderUser
+	if err := sqlx.NewBinder().BindRow(rows, &user); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+	if user != (binderUser{ID: 3, Name: "Carol", Email: "c@example.com"}) {
+		t.Errorf("BindRow = %+v", user)
+	}
+}
+
+type BinderTimestamps struct {
+	CreatedAt string `db:"created_at"`
+	UpdatedAt string `db:"updated_at"`
+}
+
+type binderAddress struct {
+	City string `db:"city"`
+	Zip  string `db:"zip"`
+}
+
+type binderBase struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type binderAccount struct {
+	binderBase
+	*BinderTimestamps
+	Name    string         `db:"name"`
+	Home    binderAddress  `db:"home"`
+	Billing *binderAddress `db:"billing"`
+}
+
+func TestBinderNestedStructs(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"id", "name", "created_at", "home.city", "home_zip", "billing.city", "billing_zip"},
+		data: [][]any{
+			{int64(1), "outer", "2024-01-01", "Paris", "75001", nil, nil},
+			{int64(2), "second", "2024-01-02", "Rome", "00100", "Milan", "20121"},
+		},
+	}}
+
+	var accounts []*binderAccount
+	if err := sqlx.NewBinder().BindRows(rows, &accounts); err != nil {
+		t.Fatalf("BindRows failed: %v", err)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("BindRows returned %d accounts, want 2", len(accounts))
+	}
+
+	first := accounts[0]
+	if first.ID != 1 || first.Name != "outer" || first.binderBase.Name != "" {
+		t.Errorf("shadowed fields bound incorrectly: %+v", first)
+	}
+	if first.BinderTimestamps == nil || first.CreatedAt != "2024-01-01" {
+		t.Errorf("embedded pointer not allocated: %+v", first.BinderTimestamps)
+	}
+	if first.Home != (binderAddress{City: "Paris", Zip: "75001"}) {
+		t.Errorf("Home = %+v", first.Home)
+	}
+	if first.Billing != nil {
+		t.Errorf("Billing = %+v, want nil for NULL columns", first.Billing)
+	}
+
+	second := accounts[1]
+	if second.Billing == nil || *second.Billing != (binderAddress{City: "Milan", Zip: "20121"}) {
+		t.Errorf("Billing = %+v", second.Billing)
+	}
+}
+
+type binderAmbiguous struct {
+	binderAddress
+	Other struct {
+		City string `db:"city"`
+	} `db:"-"`
+	binderCity
+}
+
+type binderCity struct {
+	City string `db:"city"`
+}
+
+func TestBinderAmbiguousFields(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"city", "zip"},
+		data:    [][]any{{"Oslo", "0150"}},
+	}}
+	rows.Next()
+
+	var dest binderAmbiguous
+	if err := sqlx.NewBinder().BindRow(rows, &dest); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+	if dest.binderAddress.City != "" || dest.binderCity.City != "" {
+		t.Errorf("ambiguous column city should be dropped, got %+v", dest)
+	}
+	if dest.Zip != "0150" {
+		t.Errorf("Zip = %q, want 0150", dest.Zip)
+	}
+}
+
+// upperString is a custom sql.Scanner.
+type upperString string
+
+func (u *upperString) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*u = upperString(strings.ToUpper(v))
+	case []byte:
+		*u = upperString(strings.ToUpper(string(v)))
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	return nil
+}
+
+type binderState string
+
+type binderTypes struct {
+	Count    int32          `db:"count"`
+	Big      uint64         `db:"big"`
+	Ratio    float32        `db:"ratio"`
+	Active   bool           `db:"active"`
+	Label    upperString    `db:"label"`
+	Note     sql.NullString `db:"note"`
+	Parent   *int64         `db:"parent"`
+	Alias    *string        `db:"alias"`
+	Optional *upperString   `db:"optional"`
+	State    binderState    `db:"state"`
+	Raw      []byte         `db:"raw"`
+}
+
+func TestBinderConversions(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"count", "big", "ratio", "active", "label", "note", "parent", "alias", "optional", "state", "raw"},
+		data: [][]any{
+			{[]byte("42"), []byte("18446744073709551615"), []byte("0.5"), []byte("1"), "abc", "n", int64(9), nil, []byte("x"), []byte("open"), "bytes"},
+		},
+	}}
+	rows.Next()
+
+	alias := "stale"
+	dest := binderTypes{Alias: &alias, Note: sql.NullString{String: "old", Valid: true}}
+	if err := sqlx.NewBinder().BindRow(rows, &dest); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+
+	if dest.Count != 42 || dest.Big != math.MaxUint64 || dest.Ratio != 0.5 || !dest.Active {
+		t.Errorf("numeric fields bound incorrectly: %+v", dest)
+	}
+	if dest.Label != "ABC" || dest.Optional == nil || *dest.Optional != "X" {
+		t.Errorf("scanner fields bound incorrectly: %q %v", dest.Label, dest.Optional)
+	}
+	if !dest.Note.Valid || dest.Note.String != "n" {
+		t.Errorf("Note = %+v", dest.Note)
+	}
+	if dest.Parent == nil || *dest.Parent != 9 {
+		t.Errorf("Parent = %v, want 9", dest.Parent)
+	}
+	if dest.Alias != nil {
+		t.Errorf("Alias = %v, want nil for NULL", *dest.Alias)
+	}
+	if dest.State != "open" || string(dest.Raw) != "bytes" {
+		t.Errorf("State = %q, Raw = %q", dest.State, dest.Raw)
+	}
+
+	nullRows := &namedRows{fakeRows{
+		columns: []string{"note", "count"},
+		data:    [][]any{{nil, nil}},
+	}}
+	nullRows.Next()
+	if err := sqlx.NewBinder().BindRow(nullRows, &dest); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+	if dest.Note.Valid || dest.Count != 0 {
+		t.Errorf("NULL should reset fields, got Note = %+v, Count = %d", dest.Note, dest.Count)
+	}
+}
+
+func TestBinderConversionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"overflow", int64(1 << 40)},
+		{"fraction", 1.5},
+		{"text", []byte("abc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := &namedRows{fakeRows{columns: []string{"count"}, data: [][]any{{tt.value}}}}
+			rows.Next()
+
+			var dest binderTypes
+			err := sqlx.NewBinder().BindRow(rows, &dest)
+			if !errors.Is(err, sqlx.ErrInvalidDataType) {
+				t.Errorf("BindRow error = %v, want ErrInvalidDataType", err)
+			}
+		})
+	}
+
+	rows := &namedRows{fakeRows{columns: []string{"big"}, data: [][]any{{int64(-1)}}}}
+	rows.Next()
+	var dest binderTypes
+	if err := sqlx.NewBinder().BindRow(rows, &dest); !errors.Is(err, sqlx.ErrInvalidDataType) {
+		t.Errorf("BindRow error = %v, want ErrInvalidDataType for negative unsigned", err)
+	}
+}
+
+type binderTyped struct {
+	ID     int64          `db:"id"`
+	Name   string         `db:"name"`
+	State  binderState    `db:"state"`
+	Note   sql.NullString `db:"note"`
+	Label  upperString    `db:"label"`
+	Parent *binderBase    `db:"parent"`
+}
+
+func TestBinderDirectScan(t *testing.T) {
+	db := openFakeDB(t, map[string]fakeResult{
+		"SELECT typed": {
+			columns: []string{"id", "name", "state", "note", "label", "parent.id"},
+			notNull: []bool{true, true, true, false, true, false},
+			rows: [][]driver.Value{
+				{int64(1), []byte("Alice"), []byte("open"), nil, []byte("a"), nil},
+				{int64(2), []byte("Bob"), []byte("closed"), "n", []byte("b"), int64(1)},
+			},
+		},
+	})
+
+	rows, err := db.QueryContext(context.Background(), "SELECT typed")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+
+	var dest []binderTyped
+	if err := sqlx.NewBinder().BindRows(rows, &dest); err != nil {
+		t.Fatalf("BindRows failed: %v", err)
+	}
+	if len(dest) != 2 {
+		t.Fatalf("BindRows returned %d rows, want 2", len(dest))
+	}
+
+	first, second := dest[0], dest[1]
+	if first.ID != 1 || first.Name != "Alice" || first.State != "open" || first.Label != "A" {
+		t.Errorf("first = %+v", first)
+	}
+	if first.Note.Valid || first.Parent != nil {
+		t.Errorf("NULL columns bound incorrectly: %+v", first)
+	}
+	if !second.Note.Valid || second.Note.String != "n" || second.Parent == nil || second.Parent.ID != 1 {
+		t.Errorf("second = %+v", second)
+	}
+}
+
+func TestBinderNullableColumnsWithTypedScanType(t *testing.T) {
+	// The driver reports int64 and string scan types for columns that hold
+	// NULL, as lib/pq and pgx do; nullable and unknown columns must bind NULL
+	// as the zero value instead of failing the scan.
+	nullable := fakeResult{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Alice"},
+			{nil, nil},
+		},
+	}
+	declared := nullable
+	declared.notNull = []bool{false, false}
+
+	for name, result := range map[string]fakeResult{"unknown": nullable, "nullable": declared} {
+		t.Run(name, func(t *testing.T) {
+			query := "SELECT nullable " + name
+			db := openFakeDB(t, map[string]fakeResult{query: result})
+
+			rows, err := db.QueryContext(context.Background(), query)
+			if err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			defer rows.Close()
+
+			var users []binderUser
+			if err := sqlx.NewBinder().BindRows(rows, &users); err != nil {
+				t.Fatalf("BindRows failed: %v", err)
+			}
+			if len(users) != 2 || users[0] != (binderUser{ID: 1, Name: "Alice"}) || users[1] != (binderUser{}) {
+				t.Errorf("BindRows = %+v", users)
+			}
+		})
+	}
+}
+
+type binderTags struct {
+	ID   int64  `db:"id" json:"key"`
+	Name string `db:"name"`
+}
+
+func TestBinderTagSettingsAreCachedSeparately(t *testing.T) {
+	bind := func(binder *sqlx.Binder) []string {
+		rows := &namedRows{fakeRows{columns: []string{"id", "key", "name"}, data: [][]any{{int64(1), int64(2), "x"}}}}
+		rows.Next()
+		var dest binderTags
+		if err := binder.BindRow(rows, &dest); err != nil {
+			t.Fatalf("BindRow failed: %v", err)
+		}
+		return []string{fmt.Sprint(dest.ID), dest.Name}
+	}
+
+	if got := bind(sqlx.NewBinder()); got[0] != "1" || got[1] != "x" {
+		t.Errorf("db tags bound %v", got)
+	}
+	if got := bind(sqlx.NewBinder().WithTagName("json")); got[0] != "2" || got[1] != "x" {
+		t.Errorf("json tags bound %v", got)
+	}
+	if got := bind(sqlx.NewBinder().WithTagName("json").WithUseFieldNames(false)); got[0] != "2" || got[1] != "" {
+		t.Errorf("json tags without field names bound %v", got)
+	}
+}
+
+func TestBinderConcurrentUse(t *testing.T) {
+	binder := sqlx.NewBinder()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			rows := &namedRows{fakeRows{columns: []string{"id", "name"}, data: [][]any{{id, "x"}}}}
+			var users []binderUser
+			if err := binder.BindRows(rows, &users); err != nil || len(users) != 1 || users[0].ID != id {
+				errs <- fmt.Errorf("BindRows(%d) = %+v, %v", id, users, err)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+type benchUser struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Email     string    `db:"email"`
+	Age       int       `db:"age"`
+	Score     float64   `db:"score"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// benchUserRows returns a result set of n users served by the fake driver.
+func benchUserRows(n int) fakeResult {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := fakeResult{
+		columns: []string{"id", "name", "email", "age", "score", "active", "created_at"},
+		notNull: []bool{true, true, true, true, true, true, true},
+	}
+	for i := 0; i < n; i++ {
+		result.rows = append(result.rows, []driver.Value{
+			int64(i), []byte("user"), []byte("user@example.com"), int64(30), 1.5, true, created,
+		})
+	}
+	return result
+}
+
+func BenchmarkBindRows(b *testing.B) {
+	db := openFakeDB(b, map[string]fakeResult{"SELECT bench users": benchUserRows(100)})
+	ctx := context.Background()
+	binder := sqlx.NewBinder()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.QueryContext(ctx, "SELECT bench users")
+		if err != nil {
+			b.Fatal(err)
+		}
+		var users []benchUser
+		if err := binder.BindRows(rows, &users); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkBindRow(b *testing.B) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"id", "name", "email", "age", "score", "active", "created_at"},
+		data:    [][]any{{int64(1), []byte("user"), []byte("user@example.com"), int64(30), 1.5, true, time.Now()}},
+	}}
+	rows.Next()
+	binder := sqlx.NewBinder()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var user benchUser
+		if err := binder.BindRow(rows, &user); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type binderSettings struct {
+	Theme string `json:"theme"`
+	Size  int    `json:"size"`
+}
+
+type binderDocument struct {
+	ID       int64           `db:"id,pk"`
+	Settings binderSettings  `db:"settings,json"`
+	Labels   map[string]int  `db:"labels,json"`
+	Tags     []string        `db:"tags,json"`
+	Extra    *binderSettings `db:"extra,json"`
+}
+
+func TestBinderJSONFields(t *testing.T) {
+	rows := &namedRows{fakeRows{
+		columns: []string{"id", "settings", "labels", "tags", "extra"},
+		data: [][]any{
+			{int64(1), []byte(`{"theme":"dark","size":2}`), `{"a":1}`, []byte(`["x","y"]`), nil},
+		},
+	}}
+	rows.Next()
+
+	dest := binderDocument{Labels: map[string]int{"stale": 1}}
+	if err := sqlx.NewBinder().BindRow(rows, &dest); err != nil {
+		t.Fatalf("BindRow failed: %v", err)
+	}
+	if dest.Settings != (binderSettings{Theme: "dark", Size: 2}) {
+		t.Errorf("Settings = %+v", dest.Settings)
+	}
+	if len(dest.Labels) != 1 || dest.Labels["a"] != 1 {
+		t.Errorf("Labels = %v, want only a=1", dest.Labels)
+	}
+	if len(dest.Tags) != 2 || dest.Tags[1] != "y" || dest.Extra != nil {
+		t.Errorf("Tags = %v, Extra = %v", dest.Tags, dest.Extra)
+	}
+
+	bad := &namedRows{fakeRows{columns: []string{"labels"}, data: [][]any{{[]byte(`["not a map"]`)}}}}
+	bad.Next()
+	err := sqlx.NewBinder().BindRow(bad, &dest)
+	if !errors.Is(err, sqlx.ErrInvalidDataType) || !strings.Contains(err.Error(), "labels") || !strings.Contains(err.Error(), "map[string]int") {
+		t.Errorf("BindRow error = %v, want ErrInvalidDataType naming the column and type", err)
+	}
+}
@@ -0,0 +1,368 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SelectBuilder builds SELECT statements for a specific driver.
+// Identifiers are escaped with the driver's IdentifierEscaper and values are
+// always bound as arguments. Errors are reported by ToSQL.
+type SelectBuilder struct {
+	driver   Driver
+	distinct bool
+	columns  []selectColumn
+	table    string
+	joins    []joinClause
+	where    []Condition
+	groupBy  []string
+	having   []Condition
+	orderBy  []string
+	limit    int
+	offset   int
+	keyset   *Keyset
+	lock     *Lock
+}
+
+// selectColumn is an entry in the select list: a column name to escape or a raw expression.
+type selectColumn struct {
+	expr string
+	raw  bool
+}
+
+// joinClause is a single JOIN in a SelectBuilder.
+type joinClause struct {
+	kind  string
+	table string
+	on    Condition
+}
+
+// NewSelectBuilder creates a SelectBuilder for the given driver.
+func NewSelectBuilder(driver Driver) *SelectBuilder {
+	return &SelectBuilder{
+		driver: driver,
+		limit:  -1,
+	}
+}
+
+// NewSelectBuilder creates a SelectBuilder for this connection's driver.
+func (db *DB) NewSelectBuilder() *SelectBuilder {
+	return NewSelectBuilder(db.config.Driver)
+}
+
+// Columns adds columns to the select list. Columns may be qualified, as in
+// "u.id", and "*" or "u.*" select all columns.
+// An empty select list renders as "*".
+func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	for _, column := range columns {
+		b.columns = append(b.columns, selectColumn{expr: column})
+	}
+	return b
+}
+
+// ColumnExpr adds a raw expression such as "COUNT(*) AS total" to the select
+// list. The expression is inserted verbatim and must not contain untrusted input.
+func (b *SelectBuilder) ColumnExpr(expr string) *SelectBuilder {
+	b.columns = append(b.columns, selectColumn{expr: expr, raw: true})
+	return b
+}
+
+// Distinct makes the builder emit SELECT DISTINCT.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	b.distinct = true
+	return b
+}
+
+// From sets the table to select from. The table may be schema-qualified and an
+// alias may follow it, as in "users u", "users AS u" or "analytics.events e".
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Join adds an INNER JOIN with the given ON condition.
+func (b *SelectBuilder) Join(table string, on Condition) *SelectBuilder {
+	b.joins = append(b.joins, joinClause{kind: "INNER JOIN", table: table, on: on})
+	return b
+}
+
+// LeftJoin adds a LEFT JOIN with the given ON condition.
+func (b *SelectBuilder) LeftJoin(table string, on Condition) *SelectBuilder {
+	b.joins = append(b.joins, joinClause{kind: "LEFT JOIN", table: table, on: on})
+	return b
+}
+
+// Where adds a condition to the WHERE clause. Multiple calls are combined with AND.
+func (b *SelectBuilder) Where(condition Condition) *SelectBuilder {
+	b.where = append(b.where, condition)
+	return b
+}
+
+// GroupBy adds columns to the GROUP BY clause.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// Having adds a condition to the HAVING clause. Multiple calls are combined
+// with AND. Use Raw for aggregate expressions, e.g. Raw("COUNT(*) > ?", 5).
+func (b *SelectBuilder) Having(condition Condition) *SelectBuilder {
+	b.having = append(b.having, condition)
+	return b
+}
+
+// OrderBy adds columns to the ORDER BY clause. Each entry is a column name
+// optionally followed by ASC or DESC, e.g. OrderBy("created_at DESC", "id").
+func (b *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, columns...)
+	return b
+}
+
+// Limit sets the maximum number of rows returned. A negative value removes the limit.
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+// Offset sets the number of rows to skip.
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+// Lock adds a row locking clause such as FOR UPDATE SKIP LOCKED, rendered
+// after LIMIT. The statement must run in a transaction.
+func (b *SelectBuilder) Lock(lock Lock) *SelectBuilder {
+	b.lock = &lock
+	return b
+}
+
+// Keyset paginates the query by key columns. The keyset supplies the ORDER BY
+// and LIMIT clauses, so it cannot be combined with OrderBy, Limit or Offset.
+func (b *SelectBuilder) Keyset(keyset *Keyset) *SelectBuilder {
+	b.keyset = keyset
+	return b
+}
+
+// ToSQL renders the statement with the driver's placeholder style.
+func (b *SelectBuilder) ToSQL() (string, []any, error) {
+	if b.table == "" {
+		return "", nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	where, orderBy, limit := b.where, b.orderBy, b.limit
+	if b.keyset != nil {
+		if len(b.orderBy) > 0 || b.limit >= 0 || b.offset > 0 {
+			return "", nil, fmt.Errorf("%w: keyset pagination cannot be combined with ORDER BY, LIMIT or OFFSET", ErrInvalidArguments)
+		}
+
+		after, keysetOrder, err := b.keyset.build()
+		if err != nil {
+			return "", nil, err
+		}
+		where = append(where[:len(where):len(where)], after)
+		orderBy, limit = keysetOrder, b.keyset.limit
+	}
+
+	var builder strings.Builder
+	var args []any
+
+	builder.WriteString("SELECT ")
+	if b.distinct {
+		builder.WriteString("DISTINCT ")
+	}
+
+	columnList, err := b.buildColumns()
+	if err != nil {
+		return "", nil, err
+	}
+	builder.WriteString(columnList)
+
+	table, err := escapeTableRef(b.driver, b.table)
+	if err != nil {
+		return "", nil, err
+	}
+	builder.WriteString(" FROM ")
+	builder.WriteString(table)
+
+	for _, join := range b.joins {
+		joinTable, err := escapeTableRef(b.driver, join.table)
+		if err != nil {
+			return "", nil, err
+		}
+
+		on, onArgs, err := buildWhereClauseWithDriver(b.driver, join.on)
+		if err != nil {
+			return "", nil, fmt.Errorf("build join condition: %w", err)
+		}
+		if on == "" {
+			return "", nil, fmt.Errorf("%w: join on %q requires a condition", ErrInvalidArguments, join.table)
+		}
+
+		builder.WriteString(" " + join.kind + " " + joinTable + " ON " + on)
+		args = append(args, onArgs...)
+	}
+
+	whereClause, whereArgs, err := And(where...).ToSQL(b.driver)
+	if err != nil {
+		return "", nil, fmt.Errorf("build where clause: %w", err)
+	}
+	if whereClause != "" {
+		builder.WriteString(" WHERE " + whereClause)
+		args = append(args, whereArgs...)
+	}
+
+	if len(b.groupBy) > 0 {
+		groupBy, err := escapeColumnsWithDriver(b.driver, b.groupBy)
+		if err != nil {
+			return "", nil, fmt.Errorf("build group by: %w", err)
+		}
+		builder.WriteString(" GROUP BY " + strings.Join(groupBy, ", "))
+	}
+
+	havingClause, havingArgs, err := And(b.having...).ToSQL(b.driver)
+	if err != nil {
+		return "", nil, fmt.Errorf("build having clause: %w", err)
+	}
+	if havingClause != "" {
+		builder.WriteString(" HAVING " + havingClause)
+		args = append(args, havingArgs...)
+	}
+
+	if len(orderBy) == 0 && (limit >= 0 || b.offset > 0) && supports(b.driver, FeaturePaginationRequiresOrderBy) {
+		return "", nil, fmt.Errorf("%w: LIMIT/OFFSET for %s requires ORDER BY", ErrInvalidArguments, b.driver)
+	}
+
+	if len(orderBy) > 0 {
+		orderClause, err := buildOrderByWithDriver(b.driver, orderBy)
+		if err != nil {
+			return "", nil, err
+		}
+		builder.WriteString(" ORDER BY " + orderClause)
+	}
+
+	limitClause, err := buildLimitWithDriver(b.driver, limit, b.offset)
+	if err != nil {
+		return "", nil, err
+	}
+	builder.WriteString(limitClause)
+
+	if b.lock != nil {
+		lockClause, err := LockClauseWithDriver(b.driver, *b.lock)
+		if err != nil {
+			return "", nil, err
+		}
+		builder.WriteString(" " + lockClause)
+	}
+
+	return Rebind(b.driver, builder.String()), args, nil
+}
+
+// Query renders the statement and executes it on the given executor.
+// A locking statement is refused with ErrInvalidOperation on a DB; run it on a Tx.
+func (b *SelectBuilder) Query(ctx context.Context, exec Querier) (*sql.Rows, error) {
+	if _, ok := exec.(*DB); ok && b.lock != nil {
+		return nil, fmt.Errorf("%w: locking select outside a transaction", ErrInvalidOperation)
+	}
+
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return exec.Query(ctx, query, args...)
+}
+
+// buildColumns renders the select list.
+func (b *SelectBuilder) buildColumns() (string, error) {
+	if len(b.columns) == 0 {
+		return "*", nil
+	}
+
+	parts := make([]string, len(b.columns))
+	for i, column := range b.columns {
+		if column.raw {
+			parts[i] = column.expr
+			continue
+		}
+
+		escaped, err := EscapeColumnNameWithStar(b.driver, column.expr)
+		if err != nil {
+			return "", fmt.Errorf("escape column %q: %w", column.expr, err)
+		}
+		parts[i] = escaped
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// escapeTableRef escapes a table reference with an optional alias,
+// accepting "table", "table alias" and "table AS alias".
+func escapeTableRef(driver Driver, ref string) (string, error) {
+	fields := strings.Fields(ref)
+	if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+		fields = []string{fields[0], fields[2]}
+	}
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", fmt.Errorf("%w: invalid table reference %q", ErrInvalidArguments, ref)
+	}
+
+	table, err := EscapeTableName(driver, fields[0])
+	if err != nil {
+		return "", fmt.Errorf("escape table name %q: %w", fields[0], err)
+	}
+	if len(fields) == 1 {
+		return table, nil
+	}
+
+	alias, err := GetIdentifierEscaper(driver).Escape(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("escape table alias %q: %w", fields[1], err)
+	}
+	return table + " " + alias, nil
+}
+
+// buildOrderByWithDriver renders ORDER BY entries of the form "column [ASC|DESC]".
+func buildOrderByWithDriver(driver Driver, columns []string) (string, error) {
+	parts := make([]string, len(columns))
+	for i, entry := range columns {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 || len(fields) > 2 {
+			return "", fmt.Errorf("%w: invalid order by %q", ErrInvalidArguments, entry)
+		}
+
+		escaped, err := EscapeColumnName(driver, fields[0])
+		if err != nil {
+			return "", fmt.Errorf("escape column %q: %w", fields[0], err)
+		}
+
+		direction := "ASC"
+		if len(fields) == 2 {
+			direction = strings.ToUpper(fields[1])
+			if direction != "ASC" && direction != "DESC" {
+				return "", fmt.Errorf("%w: invalid sort direction %q", ErrInvalidArguments, fields[1])
+			}
+		}
+		parts[i] = escaped + " " + direction
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// buildLimitWithDriver renders LIMIT/OFFSET with the driver's dialect.
+// A negative limit means no limit.
+func buildLimitWithDriver(driver Driver, limit, offset int) (string, error) {
+	if offset < 0 {
+		return "", fmt.Errorf("%w: offset must be >= 0", ErrInvalidArguments)
+	}
+
+	render := standardDialect{}.Limit
+	if dialect := lookupDialect(driver); dialect != nil {
+		render = dialect.Limit
+	}
+
+	clause := render(limit, offset)
+	if clause == "" {
+		return "", nil
+	}
+	return " " + clause, nil
+}
@@ -0,0 +1,111 @@
+package sqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *sqlx.SelectBuilder
+		expected string
+		args     int
+	}{
+		{
+			name:     "select all",
+			builder:  sqlx.NewSelectBuilder(sqlx.MySQL).From("users"),
+			expected: "SELECT * FROM `users`",
+		},
+		{
+			name: "full query postgres",
+			builder: sqlx.NewSelectBuilder(sqlx.PostgreSQL).
+				Columns("id", "name").
+				From("users").
+				Where(sqlx.Col("age").Gte(18)).
+				Where(sqlx.Col("state").In("new", "open")).
+				OrderBy("created_at DESC", "id").
+				Limit(20).
+				Offset(40),
+			expected: `SELECT "id", "name" FROM "users" WHERE "age" >= $1 AND "state" IN ($2, $3) ORDER BY "created_at" DESC, "id" ASC LIMIT 20 OFFSET 40`,
+			args:     3,
+		},
+		{
+			name: "join with alias and grouping",
+			builder: sqlx.NewSelectBuilder(sqlx.MySQL).
+				Columns("user_id").
+				ColumnExpr("COUNT(*) AS total").
+				From("orders AS o").
+				LeftJoin("users u", sqlx.Raw("u.id = o.user_id")).
+				GroupBy("user_id").
+				Having(sqlx.Raw("COUNT(*) > ?", 5)),
+			expected: "SELECT `user_id`, COUNT(*) AS total FROM `orders` `o` LEFT JOIN `users` `u` ON u.id = o.user_id GROUP BY `user_id` HAVING COUNT(*) > ?",
+			args:     1,
+		},
+		{
+			name: "qualified identifiers",
+			builder: sqlx.NewSelectBuilder(sqlx.PostgreSQL).
+				Columns("u.*", "o.total").
+				From("analytics.users u").
+				Join("analytics.orders o", sqlx.Col("o.user_id").EqCol("u.id")).
+				Where(sqlx.Col("u.state").Eq("open")).
+				OrderBy("o.total DESC"),
+			expected: `SELECT "u".*, "o"."total" FROM "analytics"."users" "u" INNER JOIN "analytics"."orders" "o" ON "o"."user_id" = "u"."id" WHERE "u"."state" = $1 ORDER BY "o"."total" DESC`,
+			args:     1,
+		},
+		{
+			name:     "distinct",
+			builder:  sqlx.NewSelectBuilder(sqlx.SQLite).Distinct().Columns("email").From("users"),
+			expected: `SELECT DISTINCT "email" FROM "users"`,
+		},
+		{
+			name:     "offset without limit on mysql",
+			builder:  sqlx.NewSelectBuilder(sqlx.MySQL).From("users").Offset(10),
+			expected: "SELECT * FROM `users` LIMIT 18446744073709551615 OFFSET 10",
+		},
+		{
+			name:     "offset without limit on postgres",
+			builder:  sqlx.NewSelectBuilder(sqlx.PostgreSQL).From("users").Offset(10),
+			expected: `SELECT * FROM "users" OFFSET 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.builder.ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("ToSQL() = %q, want %q", query, tt.expected)
+			}
+			if len(args) != tt.args {
+				t.Errorf("ToSQL() returned %d args, want %d", len(args), tt.args)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *sqlx.SelectBuilder
+		want    error
+	}{
+		{"missing table", sqlx.NewSelectBuilder(sqlx.MySQL), sqlx.ErrInvalidArguments},
+		{"bad direction", sqlx.NewSelectBuilder(sqlx.MySQL).From("users").OrderBy("id SIDEWAYS"), sqlx.ErrInvalidArguments},
+		{"join without condition", sqlx.NewSelectBuilder(sqlx.MySQL).From("users").Join("orders", nil), sqlx.ErrInvalidArguments},
+		{"invalid column", sqlx.NewSelectBuilder(sqlx.MySQL).From("users").Columns("id; --"), sqlx.ErrInvalidIdentifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.ToSQL()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ToSQL() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
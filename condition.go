@@ -0,0 +1,453 @@
+package sqlx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Condition is a boolean SQL expression usable in WHERE clauses.
+// ToSQL renders the expression for the given driver with '?' placeholders;
+// the statement that embeds it is rebound to the driver's style as a whole.
+type Condition interface {
+	ToSQL(driver Driver) (string, []any, error)
+}
+
+// ToSQL renders the map as an implicit AND of equality comparisons.
+// Columns are rendered in sorted order so the generated SQL is stable.
+func (m Map) ToSQL(driver Driver) (string, []any, error) {
+	if len(m) == 0 {
+		return "", nil, nil
+	}
+
+	columns := make([]string, 0, len(m))
+	for column := range m {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	clauses := make([]string, 0, len(m))
+	args := make([]any, 0, len(m))
+
+	for _, column := range columns {
+		escaped, err := EscapeColumnName(driver, column)
+		if err != nil {
+			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
+		}
+		sql, valueArgs, err := bindValue(driver, m[column])
+		if err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", column, err)
+		}
+		clauses = append(clauses, fmt.Sprintf("%s = %s", escaped, sql))
+		args = append(args, valueArgs...)
+	}
+
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// Col references a column when building conditions, e.g. Col("age").Gt(18).
+type Col string
+
+// Eq builds a "column = value" condition.
+func (c Col) Eq(value any) Condition {
+	return &compareCondition{column: string(c), op: "=", value: value}
+}
+
+// Ne builds a "column <> value" condition.
+func (c Col) Ne(value any) Condition {
+	return &compareCondition{column: string(c), op: "<>", value: value}
+}
+
+// Gt builds a "column > value" condition.
+func (c Col) Gt(value any) Condition {
+	return &compareCondition{column: string(c), op: ">", value: value}
+}
+
+// Gte builds a "column >= value" condition.
+func (c Col) Gte(value any) Condition {
+	return &compareCondition{column: string(c), op: ">=", value: value}
+}
+
+// Lt builds a "column < value" condition.
+func (c Col) Lt(value any) Condition {
+	return &compareCondition{column: string(c), op: "<", value: value}
+}
+
+// Lte builds a "column <= value" condition.
+func (c Col) Lte(value any) Condition {
+	return &compareCondition{column: string(c), op: "<=", value: value}
+}
+
+// EqCol builds a "column = other" condition comparing two columns,
+// typically used as a join condition.
+func (c Col) EqCol(other string) Condition {
+	return &columnCondition{left: string(c), op: "=", right: other}
+}
+
+// Like builds a "column LIKE pattern" condition.
+func (c Col) Like(pattern any) Condition {
+	return &compareCondition{column: string(c), op: "LIKE", value: pattern}
+}
+
+// NotLike builds a "column NOT LIKE pattern" condition.
+func (c Col) NotLike(pattern any) Condition {
+	return &compareCondition{column: string(c), op: "NOT LIKE", value: pattern}
+}
+
+// In builds a "column IN (...)" condition. A single slice argument is
+// expanded into its elements. An empty value list is an error.
+func (c Col) In(values ...any) Condition {
+	return &inCondition{column: string(c), values: flattenValues(values)}
+}
+
+// NotIn builds a "column NOT IN (...)" condition. A single slice argument is
+// expanded into its elements. An empty value list is an error.
+func (c Col) NotIn(values ...any) Condition {
+	return &inCondition{column: string(c), values: flattenValues(values), not: true}
+}
+
+// Between builds a "column BETWEEN low AND high" condition.
+func (c Col) Between(low, high any) Condition {
+	return &betweenCondition{column: string(c), low: low, high: high}
+}
+
+// NotBetween builds a "column NOT BETWEEN low AND high" condition.
+func (c Col) NotBetween(low, high any) Condition {
+	return &betweenCondition{column: string(c), low: low, high: high, not: true}
+}
+
+// IsNull builds a "column IS NULL" condition.
+func (c Col) IsNull() Condition {
+	return &nullCondition{column: string(c)}
+}
+
+// IsNotNull builds a "column IS NOT NULL" condition.
+func (c Col) IsNotNull() Condition {
+	return &nullCondition{column: string(c), not: true}
+}
+
+// And combines conditions with AND. Nil and empty conditions are skipped.
+func And(conditions ...Condition) Condition {
+	return &junction{op: "AND", conditions: conditions}
+}
+
+// Or combines conditions with OR. Nil and empty conditions are skipped.
+func Or(conditions ...Condition) Condition {
+	return &junction{op: "OR", conditions: conditions}
+}
+
+// Not negates a condition.
+func Not(condition Condition) Condition {
+	return &notCondition{condition: condition}
+}
+
+// Raw builds a condition from a SQL fragment and its arguments. The fragment
+// is inserted verbatim, so it must never contain untrusted input; use '?'
+// placeholders for values.
+func Raw(sql string, args ...any) Condition {
+	return &rawCondition{sql: sql, args: args}
+}
+
+// Expression is a SQL expression used in place of a bound value in data and
+// where maps and in comparisons. Create one with Expr.
+type Expression struct {
+	sql  string
+	args []any
+}
+
+// Expr builds an expression that is inlined where a value would otherwise be
+// bound, e.g. Map{"updated_at": Expr("NOW()"), "counter": Expr("counter + ?", 1)}.
+// Its arguments are merged in order with the surrounding arguments and must
+// match its '?' placeholders, or building the query fails. Like Raw,
+// the SQL is inserted verbatim and must never contain untrusted input.
+func Expr(sql string, args ...any) Expression {
+	return Expression{sql: sql, args: args}
+}
+
+// SQL returns the expression's SQL fragment.
+func (e Expression) SQL() string {
+	return e.sql
+}
+
+// Args returns the expression's arguments.
+func (e Expression) Args() []any {
+	return e.args
+}
+
+// bindValue returns the SQL for a value and its arguments: the inlined SQL
+// of an Expression, or a single '?' placeholder for anything else. An
+// Expression whose placeholders do not match its arguments is an error.
+func bindValue(driver Driver, value any) (string, []any, error) {
+	if e, ok := value.(Expression); ok {
+		if n := countPlaceholders(driver, e.sql); n != len(e.args) {
+			return "", nil, fmt.Errorf("%w: expression %q has %d placeholders but %d arguments", ErrInvalidArguments, e.sql, n, len(e.args))
+		}
+		return e.sql, e.args, nil
+	}
+	return "?", []any{value}, nil
+}
+
+// bindArgCount returns the number of arguments bindValue binds for value.
+func bindArgCount(value any) int {
+	if e, ok := value.(Expression); ok {
+		return len(e.args)
+	}
+	return 1
+}
+
+// compareCondition renders a binary comparison against a bound value.
+type compareCondition struct {
+	column string
+	op     string
+	value  any
+}
+
+// ToSQL implements Condition.
+func (c *compareCondition) ToSQL(driver Driver) (string, []any, error) {
+	escaped, err := EscapeColumnName(driver, c.column)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.column, err)
+	}
+	sql, args, err := bindValue(driver, c.value)
+	if err != nil {
+		return "", nil, fmt.Errorf("column %q: %w", c.column, err)
+	}
+	return fmt.Sprintf("%s %s %s", escaped, c.op, sql), args, nil
+}
+
+// columnCondition renders a comparison between two columns.
+type columnCondition struct {
+	left, op, right string
+}
+
+// ToSQL implements Condition.
+func (c *columnCondition) ToSQL(driver Driver) (string, []any, error) {
+	left, err := EscapeColumnName(driver, c.left)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.left, err)
+	}
+	right, err := EscapeColumnName(driver, c.right)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.right, err)
+	}
+	return fmt.Sprintf("%s %s %s", left, c.op, right), nil, nil
+}
+
+// inCondition renders an IN or NOT IN list.
+type inCondition struct {
+	column string
+	values []any
+	not    bool
+}
+
+// ToSQL implements Condition.
+func (c *inCondition) ToSQL(driver Driver) (string, []any, error) {
+	if len(c.values) == 0 {
+		return "", nil, fmt.Errorf("%w: IN list for column %q is empty", ErrInvalidArguments, c.column)
+	}
+
+	escaped, err := EscapeColumnName(driver, c.column)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.column, err)
+	}
+
+	op := "IN"
+	if c.not {
+		op = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", escaped, op, Placeholders(len(c.values))), c.values, nil
+}
+
+// betweenCondition renders a BETWEEN or NOT BETWEEN range.
+type betweenCondition struct {
+	column    string
+	low, high any
+	not       bool
+}
+
+// ToSQL implements Condition.
+func (c *betweenCondition) ToSQL(driver Driver) (string, []any, error) {
+	escaped, err := EscapeColumnName(driver, c.column)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.column, err)
+	}
+
+	op := "BETWEEN"
+	if c.not {
+		op = "NOT BETWEEN"
+	}
+	return fmt.Sprintf("%s %s ? AND ?", escaped, op), []any{c.low, c.high}, nil
+}
+
+// nullCondition renders an IS NULL or IS NOT NULL check.
+type nullCondition struct {
+	column string
+	not    bool
+}
+
+// ToSQL implements Condition.
+func (c *nullCondition) ToSQL(driver Driver) (string, []any, error) {
+	escaped, err := EscapeColumnName(driver, c.column)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape column %q: %w", c.column, err)
+	}
+
+	if c.not {
+		return escaped + " IS NOT NULL", nil, nil
+	}
+	return escaped + " IS NULL", nil, nil
+}
+
+// junction joins child conditions with AND or OR.
+type junction struct {
+	op         string
+	conditions []Condition
+}
+
+// ToSQL implements Condition.
+func (j *junction) ToSQL(driver Driver) (string, []any, error) {
+	sql, args, _, err := j.build(driver)
+	return sql, args, err
+}
+
+// build renders the junction and reports how many non-empty parts it joined.
+func (j *junction) build(driver Driver) (string, []any, int, error) {
+	parts := make([]string, 0, len(j.conditions))
+	compound := make([]bool, 0, len(j.conditions))
+	var args []any
+
+	for _, condition := range j.conditions {
+		sql, condArgs, isCompound, err := renderCondition(driver, condition)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		compound = append(compound, isCompound)
+		args = append(args, condArgs...)
+	}
+
+	// Parenthesize compound children only when they share the junction
+	// with siblings; a lone child keeps its own precedence.
+	if len(parts) > 1 {
+		for i := range parts {
+			if compound[i] {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+	}
+
+	return strings.Join(parts, " "+j.op+" "), args, len(parts), nil
+}
+
+// notCondition negates its child condition.
+type notCondition struct {
+	condition Condition
+}
+
+// ToSQL implements Condition.
+func (c *notCondition) ToSQL(driver Driver) (string, []any, error) {
+	if c.condition == nil {
+		return "", nil, nil
+	}
+
+	sql, args, err := c.condition.ToSQL(driver)
+	if err != nil || sql == "" {
+		return "", nil, err
+	}
+	return "NOT (" + sql + ")", args, nil
+}
+
+// rawCondition is a verbatim SQL fragment with its arguments.
+type rawCondition struct {
+	sql  string
+	args []any
+}
+
+// ToSQL implements Condition.
+func (c *rawCondition) ToSQL(driver Driver) (string, []any, error) {
+	if n := countPlaceholders(driver, c.sql); n != len(c.args) {
+		return "", nil, fmt.Errorf("%w: raw condition has %d placeholders but %d arguments", ErrInvalidArguments, n, len(c.args))
+	}
+	return c.sql, c.args, nil
+}
+
+// renderCondition renders a condition and reports whether it is compound,
+// i.e. whether it needs parentheses when combined with other conditions.
+func renderCondition(driver Driver, condition Condition) (string, []any, bool, error) {
+	if condition == nil {
+		return "", nil, false, nil
+	}
+
+	if j, ok := condition.(*junction); ok {
+		sql, args, parts, err := j.build(driver)
+		return sql, args, parts > 1, err
+	}
+
+	sql, args, err := condition.ToSQL(driver)
+	if err != nil || sql == "" {
+		return "", nil, false, err
+	}
+
+	switch c := condition.(type) {
+	case Map:
+		return sql, args, len(c) > 1, nil
+	case *rawCondition:
+		return sql, args, true, nil
+	case *keysetCondition:
+		return sql, args, len(c.columns) > 1, nil
+	}
+	return sql, args, false, nil
+}
+
+// isEmptyCondition reports whether a condition renders to nothing.
+func isEmptyCondition(driver Driver, condition Condition) bool {
+	if condition == nil {
+		return true
+	}
+	sql, _, err := condition.ToSQL(driver)
+	return err == nil && sql == ""
+}
+
+// flattenValues expands a single slice argument into its elements.
+// Byte slices are treated as scalar values.
+func flattenValues(values []any) []any {
+	if len(values) != 1 || values[0] == nil {
+		return values
+	}
+
+	if _, ok := values[0].([]byte); ok {
+		return values
+	}
+
+	rv := reflect.ValueOf(values[0])
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return values
+	}
+
+	flat := make([]any, rv.Len())
+	for i := range flat {
+		flat[i] = rv.Index(i).Interface()
+	}
+	return flat
+}
+
+// countPlaceholders counts '?' placeholders outside literals and comments.
+func countPlaceholders(driver Driver, query string) int {
+	rules := lexRulesFor(driver)
+	n := 0
+	for i := 0; i < len(query); {
+		if end := skipNonCode(rules, query, i); end > i {
+			i = end
+			continue
+		}
+		if query[i] == '?' {
+			n++
+		}
+		i++
+	}
+	return n
+}
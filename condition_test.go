@@ -0,0 +1,229 @@
+package sqlx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		cond     sqlx.Condition
+		expected string
+		args     int
+	}{
+		{
+			name:     "map implicit and",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.Map{"state": "active", "age": 30},
+			expected: "`age` = ? AND `state` = ?",
+			args:     2,
+		},
+		{
+			name:     "comparison",
+			driver:   sqlx.PostgreSQL,
+			cond:     sqlx.Col("age").Gte(18),
+			expected: `"age" >= ?`,
+			args:     1,
+		},
+		{
+			name:     "in with slice",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.Col("state").In([]string{"new", "open"}),
+			expected: "`state` IN (?, ?)",
+			args:     2,
+		},
+		{
+			name:     "not in",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.Col("id").NotIn(1, 2, 3),
+			expected: "`id` NOT IN (?, ?, ?)",
+			args:     3,
+		},
+		{
+			name:     "between",
+			driver:   sqlx.SQLite,
+			cond:     sqlx.Col("score").Between(1, 10),
+			expected: `"score" BETWEEN ? AND ?`,
+			args:     2,
+		},
+		{
+			name:     "is null",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.Col("deleted_at").IsNull(),
+			expected: "`deleted_at` IS NULL",
+		},
+		{
+			name:   "nested or inside and",
+			driver: sqlx.MySQL,
+			cond: sqlx.And(
+				sqlx.Col("deleted_at").IsNull(),
+				sqlx.Or(sqlx.Col("kind").Eq("admin"), sqlx.Col("age").Gt(21)),
+			),
+			expected: "`deleted_at` IS NULL AND (`kind` = ? OR `age` > ?)",
+			args:     2,
+		},
+		{
+			name:     "not",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.Not(sqlx.Col("name").Like("test%")),
+			expected: "NOT (`name` LIKE ?)",
+			args:     1,
+		},
+		{
+			name:     "raw with args",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.And(sqlx.Raw("score > rank * ?", 2), sqlx.Map{"active": true}),
+			expected: "(score > rank * ?) AND `active` = ?",
+			args:     2,
+		},
+		{
+			name:     "empty children skipped",
+			driver:   sqlx.MySQL,
+			cond:     sqlx.And(nil, sqlx.Map{}, sqlx.Or(sqlx.Col("id").Eq(1))),
+			expected: "`id` = ?",
+			args:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.cond.ToSQL(tt.driver)
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("ToSQL() = %q, want %q", query, tt.expected)
+			}
+			if len(args) != tt.args {
+				t.Errorf("ToSQL() returned %d args, want %d", len(args), tt.args)
+			}
+		})
+	}
+}
+
+func TestConditionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cond sqlx.Condition
+		want error
+	}{
+		{"empty in", sqlx.Col("id").In([]int{}), sqlx.ErrInvalidArguments},
+		{"raw arg mismatch", sqlx.Raw("a = ? AND b = ?", 1), sqlx.ErrInvalidArguments},
+		{"expr arg mismatch", sqlx.Col("hits").Gt(sqlx.Expr("hits + ?")), sqlx.ErrInvalidArguments},
+		{"invalid column", sqlx.Col("id; DROP TABLE users").Eq(1), sqlx.ErrInvalidIdentifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.cond.ToSQL(sqlx.MySQL)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ToSQL() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildQueriesWithCondition(t *testing.T) {
+	query, args, err := sqlx.BuildDeleteQueryWithDriver(sqlx.PostgreSQL, "sessions",
+		sqlx.Or(sqlx.Col("expires_at").Lt("2024-01-01"), sqlx.Col("revoked").Eq(true)))
+	if err != nil {
+		t.Fatalf("BuildDeleteQueryWithDriver failed: %v", err)
+	}
+	expected := `DELETE FROM "sessions" WHERE "expires_at" < $1 OR "revoked" = $2`
+	if query != expected {
+		t.Errorf("Expected delete query %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 delete args, got %d", len(args))
+	}
+
+	_, _, err = sqlx.BuildDeleteQueryWithDriver(sqlx.MySQL, "sessions", sqlx.Map{})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for empty where, got %v", err)
+	}
+}
+
+func TestExprValues(t *testing.T) {
+	query, args, err := sqlx.BuildUpdateQueryWithDriver(sqlx.PostgreSQL, "counters",
+		map[string]any{"hits": sqlx.Expr("hits + ?", 5)},
+		sqlx.Map{"name": "home", "updated_at": sqlx.Expr("NOW()")})
+	if err != nil {
+		t.Fatalf("BuildUpdateQueryWithDriver failed: %v", err)
+	}
+	expected := `UPDATE "counters" SET "hits" = hits + $1 WHERE "name" = $2 AND "updated_at" = NOW()`
+	if query != expected {
+		t.Errorf("Expected update query %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != 5 || args[1] != "home" {
+		t.Errorf("Expected args [5 home], got %v", args)
+	}
+
+	query, args, err = sqlx.BuildInsertQueryWithDriver(sqlx.MySQL, "events",
+		map[string]any{"created_at": sqlx.Expr("NOW()")})
+	if err != nil {
+		t.Fatalf("BuildInsertQueryWithDriver failed: %v", err)
+	}
+	if query != "INSERT INTO `events` (`created_at`) VALUES (NOW())" || len(args) != 0 {
+		t.Errorf("Unexpected insert query %q with args %v", query, args)
+	}
+
+	query, args, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.PostgreSQL, "events", []sqlx.Map{
+		{"name": "a", "created_at": sqlx.Expr("NOW()")},
+		{"name": "b", "created_at": sqlx.Expr("to_timestamp(?)", 0)},
+	})
+	if err != nil {
+		t.Fatalf("BuildInsertManyQueryWithDriver failed: %v", err)
+	}
+	expected = `INSERT INTO "events" ("created_at", "name") VALUES (NOW(), $1), (to_timestamp($2), $3)`
+	if query != expected {
+		t.Errorf("Expected insert query %q, got %q", expected, query)
+	}
+	if len(args) != 3 {
+		t.Errorf("Expected 3 insert args, got %d", len(args))
+	}
+
+	cond, condArgs, err := sqlx.Col("expires_at").Lt(sqlx.Expr("NOW() - INTERVAL ? DAY", 7)).ToSQL(sqlx.MySQL)
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+	if cond != "`expires_at` < NOW() - INTERVAL ? DAY" || len(condArgs) != 1 {
+		t.Errorf("Unexpected condition %q with args %v", cond, condArgs)
+	}
+
+	_, _, err = sqlx.BuildUpsertQueryWithDriver(sqlx.PostgreSQL, "counters",
+		map[string]any{"name": "home", "hits": sqlx.Expr("hits + 1")}, []string{"name"}, nil)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for upsert expression, got %v", err)
+	}
+
+	_, _, err = sqlx.BuildUpdateQueryWithDriver(sqlx.MySQL, "counters",
+		map[string]any{"hits": sqlx.Expr("hits + ?", 1, 2)}, sqlx.Map{"name": "home"})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for expression arg mismatch, got %v", err)
+	}
+}
+
+func TestInsertManyChunksByBoundArgs(t *testing.T) {
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"INSERT INTO `points` (`name`, `pos`) VALUES (?, POINT(?, ?))": {rowsAffected: 1},
+	}, func(c sqlx.Config) sqlx.Config { return c.WithMaxPlaceholders(4) })
+
+	rows := []sqlx.Map{
+		{"name": "a", "pos": sqlx.Expr("POINT(?, ?)", 1, 2)},
+		{"name": "b", "pos": sqlx.Expr("POINT(?, ?)", 3, 4)},
+	}
+	n, err := db.InsertMany(context.Background(), "points", rows)
+	if err != nil || n != 2 {
+		t.Errorf("InsertMany() = %d, %v, want one row per statement", n, err)
+	}
+
+	rows = append(rows, sqlx.Map{"name": "c", "pos": sqlx.Expr("POINT(?, ?, ?, ?)", 1, 2, 3, 4)})
+	if _, err := db.InsertMany(context.Background(), "points", rows); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("InsertMany() error = %v, want ErrInvalidArguments for a row over the limit", err)
+	}
+}
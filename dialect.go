@@ -0,0 +1,597 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Dialect describes the SQL flavour spoken by a database driver. All
+// driver-specific SQL generation in this package goes through the dialect
+// registered for Config.Driver; see RegisterDialect.
+type Dialect interface {
+	// Escaper returns the identifier escaper for the dialect.
+	Escaper() IdentifierEscaper
+
+	// Placeholder returns the bind placeholder for the n-th (1-based) argument.
+	Placeholder(n int) string
+
+	// MaxPlaceholders returns the maximum number of bind parameters per statement.
+	MaxPlaceholders() int
+
+	// Limit renders a LIMIT/OFFSET clause without leading space. A negative
+	// limit means no limit; an empty string means no clause is needed.
+	Limit(limit, offset int) string
+
+	// NullSafeEqual renders a comparison of two expressions that treats two
+	// NULLs as equal.
+	NullSafeEqual(left, right string) string
+
+	// Concat renders the string concatenation of expressions.
+	Concat(exprs ...string) string
+
+	// Bool renders a boolean literal.
+	Bool(value bool) string
+
+	// Upsert renders an insert of one row of columns with '?' placeholders
+	// that resolves conflicts on conflictColumns. With no update columns,
+	// conflicting rows are skipped; otherwise the update columns take the
+	// inserted values. The table and all columns are already escaped.
+	Upsert(table string, columns, conflictColumns, updateColumns []string) (string, error)
+
+	// Returning adds a clause to an INSERT, UPDATE or DELETE query that
+	// returns the given escaped columns of the affected rows, or all columns
+	// if none are given. Only called when the dialect has FeatureReturning.
+	Returning(query string, columns []string) (string, error)
+
+	// Lock renders a row locking clause for a SELECT, such as
+	// "FOR UPDATE SKIP LOCKED". The tables are already escaped. Dialects
+	// without the requested lock return ErrDriverNotSupported.
+	Lock(strength LockStrength, wait LockWait, tables []string) (string, error)
+
+	// Literal renders a string, []byte or time.Time value as a SQL literal.
+	// It is used by Interpolate for display only, never for execution.
+	Literal(value driver.Value) string
+
+	// Supports reports whether the dialect has a feature.
+	Supports(feature Feature) bool
+}
+
+// InsertRowLimiter is implemented by dialects that cap the number of rows in
+// the VALUES list of a single INSERT, such as SQL Server. DB.InsertMany splits
+// larger inserts and BuildInsertManyQueryWithDriver rejects them.
+type InsertRowLimiter interface {
+	// MaxInsertRows returns the maximum number of rows per INSERT.
+	MaxInsertRows() int
+}
+
+// maxInsertRows returns the row limit of a driver's INSERT statements, or 0
+// if its dialect has none.
+func maxInsertRows(driver Driver) int {
+	if limiter, ok := lookupDialect(driver).(InsertRowLimiter); ok {
+		return limiter.MaxInsertRows()
+	}
+	return 0
+}
+
+// Feature is an optional capability of a Dialect.
+type Feature int
+
+const (
+	// FeatureReturning means INSERT/UPDATE/DELETE accept a RETURNING clause.
+	FeatureReturning Feature = iota
+
+	// FeatureLastInsertID means sql.Result.LastInsertId reports auto-increment keys.
+	FeatureLastInsertID
+
+	// FeatureRowValueSeek means row value comparisons such as
+	// (a, b) > (?, ?) are planned as index range scans.
+	FeatureRowValueSeek
+
+	// FeatureBackslashEscapes means backslashes escape characters in string literals.
+	FeatureBackslashEscapes
+
+	// FeatureDoubleQuotedStrings means "..." is a string literal rather than an identifier.
+	FeatureDoubleQuotedStrings
+
+	// FeatureHashComments means # starts a line comment.
+	FeatureHashComments
+
+	// FeatureDollarQuotes means $tag$...$tag$ and E'...' string literals are recognised.
+	FeatureDollarQuotes
+
+	// FeatureNestedComments means /* */ block comments nest.
+	FeatureNestedComments
+
+	// FeatureBracketIdentifiers means [...] is a quoted identifier.
+	FeatureBracketIdentifiers
+
+	// FeaturePaginationRequiresOrderBy means LIMIT/OFFSET style pagination
+	// is only valid on an ordered query.
+	FeaturePaginationRequiresOrderBy
+)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[Driver]Dialect{
+		MySQL:      &MySQLDialect{},
+		PostgreSQL: &PostgreSQLDialect{},
+		SQLite:     &SQLiteDialect{},
+		SQLServer:  &SQLServerDialect{},
+	}
+)
+
+// RegisterDialect registers the dialect used for a driver name, replacing any
+// previous registration. It lets alternative drivers reuse a built-in dialect,
+// e.g. RegisterDialect("pgx", &PostgreSQLDialect{}).
+func RegisterDialect(driver Driver, dialect Dialect) {
+	if dialect == nil {
+		panic("sqlx: RegisterDialect dialect is nil")
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[driver] = dialect
+}
+
+// GetDialect returns the dialect registered for a driver.
+func GetDialect(driver Driver) (Dialect, error) {
+	if dialect := lookupDialect(driver); dialect != nil {
+		return dialect, nil
+	}
+	return nil, fmt.Errorf("%w: no dialect registered for %q", ErrDriverNotSupported, driver)
+}
+
+// lookupDialect returns the dialect registered for a driver, or nil.
+func lookupDialect(driver Driver) Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	return dialects[driver]
+}
+
+// supports reports whether the driver's dialect has a feature. Unregistered
+// drivers have no features.
+func supports(driver Driver, feature Feature) bool {
+	dialect := lookupDialect(driver)
+	return dialect != nil && dialect.Supports(feature)
+}
+
+// standardDialect implements the ANSI-leaning behaviour shared by the
+// built-in dialects.
+type standardDialect struct{}
+
+// Placeholder returns "?".
+func (standardDialect) Placeholder(n int) string {
+	return "?"
+}
+
+// MaxPlaceholders returns 65535.
+func (standardDialect) MaxPlaceholders() int {
+	return 65535
+}
+
+// Limit renders "LIMIT n OFFSET m", omitting either part when unused.
+func (standardDialect) Limit(limit, offset int) string {
+	var parts []string
+	if limit >= 0 {
+		parts = append(parts, "LIMIT "+strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		parts = append(parts, "OFFSET "+strconv.Itoa(offset))
+	}
+	return strings.Join(parts, " ")
+}
+
+// NullSafeEqual renders "left IS NOT DISTINCT FROM right".
+func (standardDialect) NullSafeEqual(left, right string) string {
+	return left + " IS NOT DISTINCT FROM " + right
+}
+
+// Concat renders "a || b".
+func (standardDialect) Concat(exprs ...string) string {
+	return strings.Join(exprs, " || ")
+}
+
+// Bool renders TRUE or FALSE.
+func (standardDialect) Bool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// Upsert renders INSERT ... ON CONFLICT (...) DO NOTHING or
+// DO UPDATE SET c = EXCLUDED.c.
+func (standardDialect) Upsert(table string, columns, conflictColumns, updateColumns []string) (string, error) {
+	if len(updateColumns) > 0 && len(conflictColumns) == 0 {
+		return "", fmt.Errorf("%w: conflict columns are required for upsert", ErrInvalidArguments)
+	}
+
+	query := insertValues("INSERT INTO", table, columns) + " ON CONFLICT"
+	if len(conflictColumns) > 0 {
+		query += " (" + strings.Join(conflictColumns, ", ") + ")"
+	}
+
+	if len(updateColumns) == 0 {
+		return query + " DO NOTHING", nil
+	}
+
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = column + " = EXCLUDED." + column
+	}
+	return query + " DO UPDATE SET " + strings.Join(sets, ", "), nil
+}
+
+// Returning appends "RETURNING columns".
+func (standardDialect) Returning(query string, columns []string) (string, error) {
+	return query + " RETURNING " + columnListOrStar(columns), nil
+}
+
+// Lock renders "FOR UPDATE|NO KEY UPDATE|SHARE|KEY SHARE [OF tables]
+// [NOWAIT|SKIP LOCKED]".
+func (standardDialect) Lock(strength LockStrength, wait LockWait, tables []string) (string, error) {
+	return lockClause(strength, wait, tables), nil
+}
+
+// Literal renders strings with doubled quotes, bytes as '\x0a0b' (the
+// PostgreSQL bytea hex format) and times with their zone offset.
+func (standardDialect) Literal(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return quoteString(v)
+	case []byte:
+		return `'\x` + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return quoteString(v.Format("2006-01-02 15:04:05.999999-07:00"))
+	}
+	return quoteString(fmt.Sprint(value))
+}
+
+// insertValues renders "INSERT INTO table (columns) VALUES (?, ...)".
+func insertValues(insert, table string, columns []string) string {
+	return fmt.Sprintf("%s %s (%s) VALUES (%s)", insert, table, strings.Join(columns, ", "), Placeholders(len(columns)))
+}
+
+// columnListOrStar joins escaped columns, or returns "*" if there are none.
+func columnListOrStar(columns []string) string {
+	if len(columns) == 0 {
+		return "*"
+	}
+	return strings.Join(columns, ", ")
+}
+
+// MySQLDialect implements Dialect for MySQL.
+type MySQLDialect struct {
+	standardDialect
+}
+
+// Escaper returns a MySQLIdentifierEscaper.
+func (d *MySQLDialect) Escaper() IdentifierEscaper {
+	return &MySQLIdentifierEscaper{}
+}
+
+// Limit renders LIMIT/OFFSET. MySQL has no OFFSET without LIMIT, so an
+// unlimited offset uses the largest row count.
+func (d *MySQLDialect) Limit(limit, offset int) string {
+	if limit < 0 && offset > 0 {
+		return "LIMIT 18446744073709551615 OFFSET " + strconv.Itoa(offset)
+	}
+	return d.standardDialect.Limit(limit, offset)
+}
+
+// NullSafeEqual renders "left <=> right".
+func (d *MySQLDialect) NullSafeEqual(left, right string) string {
+	return left + " <=> " + right
+}
+
+// Concat renders CONCAT(a, b).
+func (d *MySQLDialect) Concat(exprs ...string) string {
+	return "CONCAT(" + strings.Join(exprs, ", ") + ")"
+}
+
+// Upsert renders INSERT IGNORE or ON DUPLICATE KEY UPDATE c = VALUES(c).
+// MySQL resolves conflicts on any unique key, so conflictColumns is unused.
+func (d *MySQLDialect) Upsert(table string, columns, conflictColumns, updateColumns []string) (string, error) {
+	if len(updateColumns) == 0 {
+		return insertValues("INSERT IGNORE INTO", table, columns), nil
+	}
+
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = column + " = VALUES(" + column + ")"
+	}
+	return insertValues("INSERT INTO", table, columns) + " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", "), nil
+}
+
+// Lock renders FOR UPDATE or FOR SHARE with OF, NOWAIT and SKIP LOCKED as
+// supported by MySQL 8.0.
+func (d *MySQLDialect) Lock(strength LockStrength, wait LockWait, tables []string) (string, error) {
+	if strength != LockForUpdate && strength != LockForShare {
+		return "", fmt.Errorf("%w: MySQL only supports FOR UPDATE and FOR SHARE locks", ErrDriverNotSupported)
+	}
+	return lockClause(strength, wait, tables), nil
+}
+
+// Literal renders strings with backslashes escaped, bytes as X'0a0b' and
+// times without a zone, as DATETIME values are stored.
+func (d *MySQLDialect) Literal(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return quoteString(strings.ReplaceAll(v, `\`, `\\`))
+	case []byte:
+		return hexBytes(v)
+	case time.Time:
+		return quoteString(v.Format("2006-01-02 15:04:05.999999"))
+	}
+	return d.standardDialect.Literal(value)
+}
+
+// Supports reports the MySQL feature set.
+func (d *MySQLDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureLastInsertID, FeatureBackslashEscapes, FeatureDoubleQuotedStrings, FeatureHashComments:
+		return true
+	}
+	return false
+}
+
+// PostgreSQLDialect implements Dialect for PostgreSQL.
+type PostgreSQLDialect struct {
+	standardDialect
+}
+
+// Escaper returns a PostgreSQLIdentifierEscaper.
+func (d *PostgreSQLDialect) Escaper() IdentifierEscaper {
+	return &PostgreSQLIdentifierEscaper{}
+}
+
+// Placeholder returns "$n".
+func (d *PostgreSQLDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Supports reports the PostgreSQL feature set.
+func (d *PostgreSQLDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureReturning, FeatureRowValueSeek, FeatureDollarQuotes, FeatureNestedComments:
+		return true
+	}
+	return false
+}
+
+// SQLiteDialect implements Dialect for SQLite.
+type SQLiteDialect struct {
+	standardDialect
+}
+
+// Escaper returns a SQLiteIdentifierEscaper.
+func (d *SQLiteDialect) Escaper() IdentifierEscaper {
+	return &SQLiteIdentifierEscaper{}
+}
+
+// MaxPlaceholders returns 32766, the default limit since SQLite 3.32.
+func (d *SQLiteDialect) MaxPlaceholders() int {
+	return 32766
+}
+
+// Limit renders LIMIT/OFFSET, using LIMIT -1 for an unlimited offset.
+func (d *SQLiteDialect) Limit(limit, offset int) string {
+	if limit < 0 && offset > 0 {
+		return "LIMIT -1 OFFSET " + strconv.Itoa(offset)
+	}
+	return d.standardDialect.Limit(limit, offset)
+}
+
+// NullSafeEqual renders "left IS right".
+func (d *SQLiteDialect) NullSafeEqual(left, right string) string {
+	return left + " IS " + right
+}
+
+// Bool renders 1 or 0.
+func (d *SQLiteDialect) Bool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// Lock returns ErrDriverNotSupported: SQLite locks the whole database and
+// has no row locking clauses.
+func (d *SQLiteDialect) Lock(strength LockStrength, wait LockWait, tables []string) (string, error) {
+	return "", fmt.Errorf("%w: SQLite does not support row locking clauses", ErrDriverNotSupported)
+}
+
+// Literal renders bytes as X'0a0b' and times in the format the common
+// SQLite drivers store.
+func (d *SQLiteDialect) Literal(value driver.Value) string {
+	switch v := value.(type) {
+	case []byte:
+		return hexBytes(v)
+	case time.Time:
+		return quoteString(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+	}
+	return d.standardDialect.Literal(value)
+}
+
+// Supports reports the SQLite feature set. RETURNING needs SQLite 3.35+.
+func (d *SQLiteDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureReturning, FeatureLastInsertID:
+		return true
+	}
+	return false
+}
+
+// SQLServerDialect implements Dialect for SQL Server.
+type SQLServerDialect struct {
+	standardDialect
+}
+
+// Escaper returns a SQLServerIdentifierEscaper.
+func (d *SQLServerDialect) Escaper() IdentifierEscaper {
+	return &SQLServerIdentifierEscaper{}
+}
+
+// Placeholder returns "@pn".
+func (d *SQLServerDialect) Placeholder(n int) string {
+	return "@p" + strconv.Itoa(n)
+}
+
+// MaxPlaceholders returns 2098: a SQL Server request takes 2100 parameters,
+// but sp_executesql uses two of them for the statement and its parameter list.
+func (d *SQLServerDialect) MaxPlaceholders() int {
+	return 2098
+}
+
+// MaxInsertRows returns 1000, the row limit of a VALUES table constructor.
+// It implements InsertRowLimiter.
+func (d *SQLServerDialect) MaxInsertRows() int {
+	return 1000
+}
+
+// Limit renders OFFSET n ROWS [FETCH NEXT m ROWS ONLY]. The query must have
+// an ORDER BY clause.
+func (d *SQLServerDialect) Limit(limit, offset int) string {
+	if limit < 0 && offset <= 0 {
+		return ""
+	}
+
+	clause := "OFFSET " + strconv.Itoa(max(offset, 0)) + " ROWS"
+	if limit >= 0 {
+		clause += " FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY"
+	}
+	return clause
+}
+
+// NullSafeEqual renders EXISTS (SELECT left INTERSECT SELECT right), which
+// treats NULLs as equal on every SQL Server version.
+func (d *SQLServerDialect) NullSafeEqual(left, right string) string {
+	return "EXISTS (SELECT " + left + " INTERSECT SELECT " + right + ")"
+}
+
+// Concat renders CONCAT(a, b).
+func (d *SQLServerDialect) Concat(exprs ...string) string {
+	return "CONCAT(" + strings.Join(exprs, ", ") + ")"
+}
+
+// Bool renders 1 or 0.
+func (d *SQLServerDialect) Bool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// Upsert renders a MERGE statement matching on conflictColumns. Without
+// update columns, matched rows are left unchanged.
+func (d *SQLServerDialect) Upsert(table string, columns, conflictColumns, updateColumns []string) (string, error) {
+	if len(conflictColumns) == 0 {
+		return "", fmt.Errorf("%w: conflict columns are required for MERGE", ErrInvalidArguments)
+	}
+
+	on := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		on[i] = "target." + column + " = source." + column
+	}
+
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = "source." + column
+	}
+
+	var builder strings.Builder
+	builder.WriteString("MERGE INTO " + table + " WITH (HOLDLOCK) AS target")
+	builder.WriteString(" USING (VALUES (" + Placeholders(len(columns)) + ")) AS source (" + strings.Join(columns, ", ") + ")")
+	builder.WriteString(" ON " + strings.Join(on, " AND "))
+
+	if len(updateColumns) > 0 {
+		sets := make([]string, len(updateColumns))
+		for i, column := range updateColumns {
+			sets[i] = "target." + column + " = source." + column
+		}
+		builder.WriteString(" WHEN MATCHED THEN UPDATE SET " + strings.Join(sets, ", "))
+	}
+
+	builder.WriteString(" WHEN NOT MATCHED THEN INSERT (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ");")
+	return builder.String(), nil
+}
+
+// Returning adds an OUTPUT clause: before VALUES for INSERT, before FROM or
+// WHERE for UPDATE and DELETE, or at the end of the statement. INSERT and
+// UPDATE output the INSERTED row, DELETE the DELETED row.
+func (d *SQLServerDialect) Returning(query string, columns []string) (string, error) {
+	stmt := parseStatement(lexRulesOf(d), query)
+
+	start := stmt.next(0)
+	if start < 0 {
+		return "", fmt.Errorf("%w: empty query", ErrInvalidQuery)
+	}
+
+	var source string
+	var at int
+	switch {
+	case stmt.tokens[start].isWord("INSERT"):
+		source = "INSERTED"
+		at = firstIndex(stmt.find(start, "VALUES"), stmt.find(start, "SELECT"), stmt.find(start, "DEFAULT", "VALUES"))
+	case stmt.tokens[start].isWord("UPDATE"):
+		source = "INSERTED"
+		at = firstIndex(stmt.find(start, "FROM"), stmt.find(start, "WHERE"), stmt.semicolon(start))
+	case stmt.tokens[start].isWord("DELETE"):
+		source = "DELETED"
+		from := stmt.find(start, "FROM")
+		at = firstIndex(stmt.find(from+1, "FROM"), stmt.find(start, "WHERE"), stmt.semicolon(start))
+	default:
+		return "", fmt.Errorf("%w: OUTPUT requires an INSERT, UPDATE or DELETE statement", ErrInvalidQuery)
+	}
+
+	outputs := []string{source + ".*"}
+	if len(columns) > 0 {
+		outputs = make([]string, len(columns))
+		for i, column := range columns {
+			outputs[i] = source + "." + column
+		}
+	}
+	output := "OUTPUT " + strings.Join(outputs, ", ")
+
+	offset := stmt.offset(at)
+	head := strings.TrimRightFunc(query[:offset], unicode.IsSpace)
+	if at < 0 {
+		return head + " " + output, nil
+	}
+	return head + " " + output + " " + query[offset:], nil
+}
+
+// Lock returns ErrDriverNotSupported: SQL Server locks rows with table hints
+// such as WITH (UPDLOCK, READPAST) rather than a trailing clause.
+func (d *SQLServerDialect) Lock(strength LockStrength, wait LockWait, tables []string) (string, error) {
+	return "", fmt.Errorf("%w: SQL Server uses table hints instead of row locking clauses", ErrDriverNotSupported)
+}
+
+// Literal renders strings as N'...' Unicode literals, bytes as 0x0a0b and
+// times as datetimeoffset values.
+func (d *SQLServerDialect) Literal(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return "N" + quoteString(v)
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		return quoteString(v.Format("2006-01-02T15:04:05.9999999-07:00"))
+	}
+	return d.standardDialect.Literal(value)
+}
+
+// Supports reports the SQL Server feature set.
+func (d *SQLServerDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureReturning, FeatureNestedComments, FeatureBracketIdentifiers, FeaturePaginationRequiresOrderBy:
+		return true
+	}
+	return false
+}
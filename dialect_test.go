@@ -0,0 +1,79 @@
+package sqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestDialectHelpers(t *testing.T) {
+	tests := []struct {
+		driver    sqlx.Driver
+		nullSafe  string
+		concat    string
+		trueValue string
+	}{
+		{sqlx.MySQL, "`deleted_at` <=> ?", "CONCAT(`first_name`, `last_name`)", "TRUE"},
+		{sqlx.PostgreSQL, `"deleted_at" IS NOT DISTINCT FROM ?`, `"first_name" || "last_name"`, "TRUE"},
+		{sqlx.SQLite, `"deleted_at" IS ?`, `"first_name" || "last_name"`, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.driver), func(t *testing.T) {
+			nullSafe, err := sqlx.NullSafeEqualWithDriver(tt.driver, "deleted_at")
+			if err != nil || nullSafe != tt.nullSafe {
+				t.Errorf("NullSafeEqualWithDriver() = %q, %v, want %q", nullSafe, err, tt.nullSafe)
+			}
+
+			concat, err := sqlx.ConcatWithDriver(tt.driver, "first_name", "last_name")
+			if err != nil || concat != tt.concat {
+				t.Errorf("ConcatWithDriver() = %q, %v, want %q", concat, err, tt.concat)
+			}
+
+			trueValue, err := sqlx.BoolWithDriver(tt.driver, true)
+			if err != nil || trueValue != tt.trueValue {
+				t.Errorf("BoolWithDriver() = %q, %v, want %q", trueValue, err, tt.trueValue)
+			}
+		})
+	}
+}
+
+func TestDialectUnknownDriver(t *testing.T) {
+	unknown := sqlx.Driver("unknown")
+
+	if _, err := sqlx.GetDialect(unknown); !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("GetDialect() error = %v, want %v", err, sqlx.ErrDriverNotSupported)
+	}
+	if _, err := sqlx.NullSafeEqualWithDriver(unknown, "id"); !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("NullSafeEqualWithDriver() error = %v, want %v", err, sqlx.ErrDriverNotSupported)
+	}
+	if _, err := sqlx.PaginateWithDriver(unknown, "SELECT 1", 1, 10); !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("PaginateWithDriver() error = %v, want %v", err, sqlx.ErrDriverNotSupported)
+	}
+	if query := sqlx.Rebind(unknown, "SELECT ?"); query != "SELECT ?" {
+		t.Errorf("Rebind() = %q, want the query unchanged", query)
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	pgx := sqlx.Driver("pgx")
+	sqlx.RegisterDialect(pgx, &sqlx.PostgreSQLDialect{})
+
+	query, _, err := sqlx.BuildSelectQueryWithDriver(pgx, "users", []string{"id"}, sqlx.Col("email").Eq("a@b.c"))
+	if err != nil {
+		t.Fatalf("BuildSelectQueryWithDriver failed: %v", err)
+	}
+	expected := `SELECT "id" FROM "users" WHERE "email" = $1`
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+
+	dialect, err := sqlx.GetDialect(pgx)
+	if err != nil {
+		t.Fatalf("GetDialect() error = %v", err)
+	}
+	if !dialect.Supports(sqlx.FeatureReturning) || dialect.Supports(sqlx.FeatureLastInsertID) {
+		t.Error("registered dialect reports the wrong feature set")
+	}
+}
@@ -114,13 +114,15 @@ func demoBasicCRUD() {
 
 	// 2. 查询单条数据
 	fmt.Println("\n2. 查询单条数据:")
-	row := sqlx.SelectOne(ctx, "game", "profile",
+	row, err := sqlx.SelectOne(ctx, "game", "profile",
 		[]string{"id", "first_name", "last_name", "created_at"},
 		sqlx.Where("first_name", "John"),
 	)
 
 	var profile BasicProfile
-	if err := sqlx.ScanRow(row, &profile.ID, &profile.FirstName, &profile.LastName, &profile.CreatedAt); err != nil {
+	if err != nil {
+		log.Printf("❌ 查询失败: %v", err)
+	} else if err := sqlx.ScanRow(row, &profile.ID, &profile.FirstName, &profile.LastName, &profile.CreatedAt); err != nil {
 		if sqlx.Is(err, sqlx.ErrNoRows) {
 			fmt.Println("✅ 未找到数据（预期中）")
 		} else {
@@ -144,8 +146,7 @@ func demoBasicCRUD() {
 
 	// 4. 删除数据
 	fmt.Println("\n4. 删除数据:")
-	where = sqlx.Where("id", ">", 100) // 清理旧数据
-	result, err = sqlx.Delete(ctx, "game", "profile", where)
+	result, err = sqlx.Delete(ctx, "game", "profile", sqlx.Col("id").Gt(100)) // 清理旧数据
 	if err != nil {
 		log.Printf("❌ 删除失败: %v", err)
 	} else {
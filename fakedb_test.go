@@ -0,0 +1,215 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+// fakeResult is a canned result set served by the fake driver.
+type fakeResult struct {
+	columns      []string
+	types        []string
+	rows         [][]driver.Value
+	notNull      []bool
+	lastInsertID int64
+	rowsAffected int64
+}
+
+// fakeDriver is a minimal database/sql driver that answers queries from a
+// table of canned results keyed by SQL text.
+type fakeDriver struct {
+	mu       sync.Mutex
+	results  map[string]fakeResult
+	lastArgs []driver.Value
+}
+
+var (
+	fakeDriverOnce sync.Once
+	fakeDB         = &fakeDriver{results: map[string]fakeResult{}}
+)
+
+// openFakeDB registers the canned results and returns a *sql.DB using them.
+func openFakeDB(t testing.TB, results map[string]fakeResult) *sql.DB {
+	t.Helper()
+
+	fakeDriverOnce.Do(func() {
+		sql.Register("sqlxfake", fakeDB)
+	})
+
+	fakeDB.mu.Lock()
+	for query, result := range results {
+		fakeDB.results[query] = result
+	}
+	fakeDB.mu.Unlock()
+
+	db, err := sql.Open("sqlxfake", "")
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// openFakeSQLX registers the canned results and returns a *sqlx.DB that uses
+// them with the dialect of the given driver. configure adjusts the config.
+func openFakeSQLX(t testing.TB, dialect sqlx.Driver, results map[string]fakeResult, configure ...func(sqlx.Config) sqlx.Config) *sqlx.DB {
+	t.Helper()
+
+	openFakeDB(t, results)
+	fakeDialects.Do(registerFakeDialects)
+
+	config := sqlx.DefaultConfig().WithDriver("sqlxfake-" + dialect).WithDSN("fake")
+	for _, fn := range configure {
+		config = fn(config)
+	}
+
+	db, err := sqlx.NewDB(config)
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+var fakeDialects sync.Once
+
+// registerFakeDialects registers the fake driver under one name per dialect.
+func registerFakeDialects() {
+	for _, driver := range []sqlx.Driver{sqlx.MySQL, sqlx.PostgreSQL, sqlx.SQLite, sqlx.SQLServer} {
+		dialect, _ := sqlx.GetDialect(driver)
+		sql.Register("sqlxfake-"+string(driver), fakeDB)
+		sqlx.RegisterDialect("sqlxfake-"+driver, dialect)
+	}
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+
+	result, ok := c.driver.results[query]
+	if !ok {
+		return nil, fmt.Errorf("fake driver: unexpected query %q", query)
+	}
+	return &fakeStmt{driver: c.driver, result: result}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Commit() error             { return nil }
+func (c *fakeConn) Rollback() error           { return nil }
+
+type fakeStmt struct {
+	driver *fakeDriver
+	result fakeResult
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.recordArgs(args)
+	return fakeExecResult(s.result), nil
+}
+
+// recordArgs stores the arguments of the latest statement.
+func (s *fakeStmt) recordArgs(args []driver.Value) {
+	s.driver.mu.Lock()
+	s.driver.lastArgs = args
+	s.driver.mu.Unlock()
+}
+
+// fakeLastArgs returns the arguments of the latest statement.
+func fakeLastArgs() []driver.Value {
+	fakeDB.mu.Lock()
+	defer fakeDB.mu.Unlock()
+	return fakeDB.lastArgs
+}
+
+// fakeExecResult reports the configured insert id and affected row count.
+type fakeExecResult fakeResult
+
+func (r fakeExecResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r fakeExecResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.recordArgs(args)
+	return &fakeDriverRows{result: s.result}, nil
+}
+
+type fakeDriverRows struct {
+	result fakeResult
+	pos    int
+}
+
+func (r *fakeDriverRows) Columns() []string { return r.result.columns }
+func (r *fakeDriverRows) Close() error      { return nil }
+
+func (r *fakeDriverRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName reports the configured type names.
+func (r *fakeDriverRows) ColumnTypeDatabaseTypeName(index int) string {
+	if index < len(r.result.types) {
+		return r.result.types[index]
+	}
+	return ""
+}
+
+// ColumnTypeScanType reports the Go type of the first non-NULL value of a
+// column, whether or not the column holds NULLs, as lib/pq and pgx do.
+func (r *fakeDriverRows) ColumnTypeScanType(index int) reflect.Type {
+	for _, row := range r.result.rows {
+		if row[index] != nil {
+			return reflect.TypeOf(row[index])
+		}
+	}
+	return reflect.TypeOf((*any)(nil)).Elem()
+}
+
+// ColumnTypeNullable reports the configured NOT NULL flags; columns without
+// one are of unknown nullability.
+func (r *fakeDriverRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if index < len(r.result.notNull) {
+		return !r.result.notNull[index], true
+	}
+	return false, false
+}
+
+// sqlQuerier adapts a *sql.DB to sqlx.Querier.
+type sqlQuerier struct {
+	db *sql.DB
+}
+
+func (q sqlQuerier) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return q.db.ExecContext(ctx, query, args...)
+}
+
+func (q sqlQuerier) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return q.db.QueryContext(ctx, query, args...)
+}
+
+func (q sqlQuerier) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return q.db.QueryRowContext(ctx, query, args...)
+}
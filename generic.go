@@ -0,0 +1,210 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Get runs a query that must return exactly one row and binds it to a T,
+// which is a struct or a pointer to a struct, using NewBinder with the time
+// settings of a DB or Tx. It returns
+// ErrNoRows for an empty result and ErrMultipleRows for more than one row.
+func Get[T any](ctx context.Context, q Querier, query string, args ...any) (T, error) {
+	var result T
+
+	rows, cancel, err := queryRows(ctx, q, query, args)
+	if err != nil {
+		return result, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("iterate rows: %w", err)
+		}
+		return result, ErrNoRows
+	}
+
+	if err := binderFor(q).BindRow(rows, structTarget(&result)); err != nil {
+		return result, err
+	}
+
+	if rows.Next() {
+		var zero T
+		return zero, ErrMultipleRows
+	}
+	if err := rows.Err(); err != nil {
+		var zero T
+		return zero, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// All runs a query and binds every row to a T, which is a struct or a
+// pointer to a struct, using NewBinder. An empty result is an empty slice.
+func All[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	rows, cancel, err := queryRows(ctx, q, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	result := []T{}
+	if err := binderFor(q).BindRows(rows, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Scalar runs a query that must return exactly one row with one column,
+// such as SELECT COUNT(*), and converts the value to T with the Binder's
+// conversion rules. It returns ErrNoRows for an empty result and
+// ErrMultipleRows for more than one row.
+func Scalar[T any](ctx context.Context, q Querier, query string, args ...any) (T, error) {
+	var result T
+
+	rows, cancel, err := queryRows(ctx, q, query, args)
+	if err != nil {
+		return result, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	if err := singleColumn(rows); err != nil {
+		return result, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("iterate rows: %w", err)
+		}
+		return result, ErrNoRows
+	}
+
+	if err := scanColumn(rows, &result, binderFor(q).Time); err != nil {
+		return result, err
+	}
+
+	if rows.Next() {
+		var zero T
+		return zero, ErrMultipleRows
+	}
+	if err := rows.Err(); err != nil {
+		var zero T
+		return zero, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// Column runs a query that returns one column and converts every value to
+// T, e.g. Column[int64](ctx, db, "SELECT id FROM users"). An empty result is
+// an empty slice.
+func Column[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	rows, cancel, err := queryRows(ctx, q, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	if err := singleColumn(rows); err != nil {
+		return nil, err
+	}
+
+	times := binderFor(q).Time
+	result := []T{}
+	for rows.Next() {
+		var value T
+		if err := scanColumn(rows, &value, times); err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// queryRows runs a query on q. For a DB the query timeout must cover reading
+// the rows, so the returned cancel function is called after the rows are closed.
+func queryRows(ctx context.Context, q Querier, query string, args []any) (*sql.Rows, context.CancelFunc, error) {
+	if db, ok := q.(*DB); ok {
+		return db.queryContext(ctx, query, args...)
+	}
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, func() {}, nil
+}
+
+// binderFor returns a default Binder with the time settings of q if it is a
+// DB or Tx.
+func binderFor(q Querier) *Binder {
+	switch q := q.(type) {
+	case *DB:
+		return q.binder()
+	case *Tx:
+		return q.binder()
+	}
+	return NewBinder()
+}
+
+// structTarget returns the pointer to bind a row to: dest itself, or a newly
+// allocated struct stored in *dest when T is a pointer type.
+func structTarget[T any](dest *T) any {
+	v := reflect.ValueOf(dest).Elem()
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		return v.Interface()
+	}
+	return dest
+}
+
+// singleColumn returns ErrInvalidQuery unless rows have exactly one column.
+func singleColumn(rows *sql.Rows) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 1 {
+		return fmt.Errorf("%w: expected 1 column, got %d", ErrInvalidQuery, len(columns))
+	}
+	return nil
+}
+
+// scanColumn scans the single column of the current row into dest. NULL
+// leaves the zero value, which is nil for pointer types. Times are read
+// with the time settings.
+func scanColumn[T any](rows *sql.Rows, dest *T, times TimeSettings) error {
+	var raw any
+	if err := rows.Scan(&raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+
+	if t := reflect.TypeOf(dest).Elem(); t == timeType || (t.Kind() == reflect.Ptr && t.Elem() == timeType) {
+		parsed, err := times.parse(raw)
+		if err != nil {
+			return fmt.Errorf("column: %w", err)
+		}
+		raw = parsed
+	}
+
+	if err := assignValue(reflect.ValueOf(dest).Elem(), raw); err != nil {
+		return fmt.Errorf("column: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+type genericUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestGenericQueries(t *testing.T) {
+	users := fakeResult{
+		columns: []string{"name", "id"},
+		rows: [][]driver.Value{
+			{[]byte("Alice"), int64(1)},
+			{[]byte("Bob"), int64(2)},
+		},
+	}
+	db := sqlQuerier{openFakeDB(t, map[string]fakeResult{
+		"SELECT users":     users,
+		"SELECT one user":  {columns: users.columns, rows: users.rows[:1]},
+		"SELECT no users":  {columns: users.columns},
+		"SELECT count":     {columns: []string{"count"}, rows: [][]driver.Value{{[]byte("2")}}},
+		"SELECT null":      {columns: []string{"value"}, rows: [][]driver.Value{{nil}}},
+		"SELECT ids":       {columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}},
+		"SELECT two cols":  {columns: []string{"a", "b"}, rows: [][]driver.Value{{int64(1), int64(2)}}},
+		"SELECT no counts": {columns: []string{"count"}},
+	})}
+	ctx := context.Background()
+
+	user, err := sqlx.Get[genericUser](ctx, db, "SELECT one user")
+	if err != nil || user != (genericUser{ID: 1, Name: "Alice"}) {
+		t.Errorf("Get() = %+v, %v", user, err)
+	}
+
+	userPtr, err := sqlx.Get[*genericUser](ctx, db, "SELECT one user")
+	if err != nil || userPtr == nil || userPtr.Name != "Alice" {
+		t.Errorf("Get[*T]() = %+v, %v", userPtr, err)
+	}
+
+	if _, err := sqlx.Get[genericUser](ctx, db, "SELECT no users"); !errors.Is(err, sqlx.ErrNoRows) {
+		t.Errorf("Get() on empty result error = %v, want ErrNoRows", err)
+	}
+	if _, err := sqlx.Get[genericUser](ctx, db, "SELECT users"); !errors.Is(err, sqlx.ErrMultipleRows) {
+		t.Errorf("Get() on two rows error = %v, want ErrMultipleRows", err)
+	}
+
+	all, err := sqlx.All[genericUser](ctx, db, "SELECT users")
+	if err != nil || len(all) != 2 || all[1] != (genericUser{ID: 2, Name: "Bob"}) {
+		t.Errorf("All() = %+v, %v", all, err)
+	}
+	none, err := sqlx.All[*genericUser](ctx, db, "SELECT no users")
+	if err != nil || none == nil || len(none) != 0 {
+		t.Errorf("All() on empty result = %#v, %v", none, err)
+	}
+
+	count, err := sqlx.Scalar[int](ctx, db, "SELECT count")
+	if err != nil || count != 2 {
+		t.Errorf("Scalar() = %d, %v", count, err)
+	}
+	nullable, err := sqlx.Scalar[*string](ctx, db, "SELECT null")
+	if err != nil || nullable != nil {
+		t.Errorf("Scalar[*string]() on NULL = %v, %v", nullable, err)
+	}
+	if _, err := sqlx.Scalar[int](ctx, db, "SELECT no counts"); !errors.Is(err, sqlx.ErrNoRows) {
+		t.Errorf("Scalar() on empty result error = %v, want ErrNoRows", err)
+	}
+	if _, err := sqlx.Scalar[int64](ctx, db, "SELECT ids"); !errors.Is(err, sqlx.ErrMultipleRows) {
+		t.Errorf("Scalar() on two rows error = %v, want ErrMultipleRows", err)
+	}
+	if _, err := sqlx.Scalar[int64](ctx, db, "SELECT two cols"); !errors.Is(err, sqlx.ErrInvalidQuery) {
+		t.Errorf("Scalar() on two columns error = %v, want ErrInvalidQuery", err)
+	}
+
+	ids, err := sqlx.Column[int64](ctx, db, "SELECT ids")
+	if err != nil || len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Column() = %v, %v", ids, err)
+	}
+	names, err := sqlx.Column[string](ctx, db, "SELECT users")
+	if !errors.Is(err, sqlx.ErrInvalidQuery) {
+		t.Errorf("Column() on two columns = %v, %v, want ErrInvalidQuery", names, err)
+	}
+}
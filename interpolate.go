@@ -0,0 +1,187 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interpolate renders query with its placeholders replaced by the args as
+// literals quoted for the driver's dialect. The query uses the driver's
+// placeholder style, as passed to Exec: '?' for MySQL and SQLite, $n for
+// PostgreSQL and @pn for SQL Server. Placeholders without a matching
+// argument are left as they are. For a driver without a registered dialect
+// the query is returned unchanged.
+//
+// The result is for display only, e.g. in logs and error messages. Never
+// execute it: quoting here is a best effort and does not replace binding.
+func Interpolate(driver Driver, query string, args []any) string {
+	dialect := lookupDialect(driver)
+	if dialect == nil {
+		return query
+	}
+	rules := lexRulesOf(dialect)
+
+	// Numbered styles share a prefix followed by the 1-based index.
+	prefix := strings.TrimSuffix(dialect.Placeholder(1), "1")
+	numbered := prefix != dialect.Placeholder(1)
+
+	var builder strings.Builder
+	builder.Grow(len(query) + 16*len(args))
+
+	n := 0
+	for i := 0; i < len(query); {
+		if end := skipNonCode(rules, query, i); end > i {
+			builder.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		if !numbered && query[i] == '?' {
+			n++
+			builder.WriteString(interpolateArg(dialect, args, n, "?"))
+			i++
+			continue
+		}
+
+		if numbered && strings.HasPrefix(query[i:], prefix) {
+			j := i + len(prefix)
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			if index, err := strconv.Atoi(query[i+len(prefix) : j]); err == nil {
+				builder.WriteString(interpolateArg(dialect, args, index, query[i:j]))
+				i = j
+				continue
+			}
+		}
+
+		builder.WriteByte(query[i])
+		i++
+	}
+
+	return builder.String()
+}
+
+// interpolateArg renders the n-th (1-based) argument, or the placeholder
+// itself if there is no such argument.
+func interpolateArg(dialect Dialect, args []any, n int, placeholder string) string {
+	if n < 1 || n > len(args) {
+		return placeholder
+	}
+	return literal(dialect, args[n-1])
+}
+
+// literal renders a single argument as a SQL literal for display.
+func literal(dialect Dialect, arg any) string {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return dialect.Literal(fmt.Sprintf("<%T: %v>", arg, err))
+		}
+		arg = value
+	}
+
+	value, err := driver.DefaultParameterConverter.ConvertValue(arg)
+	if err != nil {
+		// Not a driver value; show its default formatting as a string.
+		return dialect.Literal(fmt.Sprint(arg))
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		return dialect.Bool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return dialect.Literal(v)
+	}
+}
+
+// quoteString wraps s in single quotes, doubling embedded quotes.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// hexBytes renders b as X'0a0b'.
+func hexBytes(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+// queryHooks are the Config settings applied to each query run by a DB or Tx.
+type queryHooks struct {
+	logger        QueryLogger
+	queryInErrors bool
+}
+
+// finish passes a finished query to the logger, if any, and wraps its error
+// in a QueryError if queryInErrors is set.
+func (h queryHooks) finish(ctx context.Context, query string, args []any, start time.Time, err error) error {
+	if h.logger != nil {
+		h.logger.LogQuery(ctx, query, args, time.Since(start), err)
+	}
+	if err == nil || !h.queryInErrors {
+		return err
+	}
+	return &QueryError{Query: query, Err: err}
+}
+
+// QueryError is a failed query's error with the query's SQL, returned by DB
+// and Tx when Config.QueryInErrors is set. The SQL keeps its placeholders;
+// argument values are never included.
+type QueryError struct {
+	Query string
+	Err   error
+}
+
+// Error returns the error's message followed by the query.
+func (e *QueryError) Error() string {
+	return e.Err.Error() + " [query: " + e.Query + "]"
+}
+
+// Unwrap returns the query's error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// errorText returns the message an error classifier inspects: that of the
+// query's error if err wraps a QueryError, so that SQL text is never matched.
+func errorText(err error) string {
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		return queryErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// PrintfLogger returns a QueryLogger that writes each query through printf,
+// such as log.Printf, with its arguments interpolated for the driver.
+func PrintfLogger(driver Driver, printf func(format string, args ...any)) QueryLogger {
+	return &printfLogger{driver: driver, printf: printf}
+}
+
+// printfLogger implements QueryLogger on top of a printf-style function.
+type printfLogger struct {
+	driver Driver
+	printf func(format string, args ...any)
+}
+
+// LogQuery implements QueryLogger interface.
+func (l *printfLogger) LogQuery(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	if err != nil {
+		l.printf("sql: %s [%s]: %v", Interpolate(l.driver, query, args), duration, err)
+		return
+	}
+	l.printf("sql: %s [%s]", Interpolate(l.driver, query, args), duration)
+}
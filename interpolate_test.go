@@ -0,0 +1,199 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.FixedZone("", 2*60*60))
+	id := int64(7)
+
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		query    string
+		args     []any
+		expected string
+	}{
+		{
+			name:     "mysql scalars",
+			driver:   sqlx.MySQL,
+			query:    "SELECT * FROM t WHERE a = ? AND b = ? AND c = ? AND d = ? AND e IS ?",
+			args:     []any{42, 1.5, true, `it's a \ test`, nil},
+			expected: `SELECT * FROM t WHERE a = 42 AND b = 1.5 AND c = TRUE AND d = 'it''s a \\ test' AND e IS NULL`,
+		},
+		{
+			name:     "mysql bytes and time",
+			driver:   sqlx.MySQL,
+			query:    "INSERT INTO t (b, at) VALUES (?, ?)",
+			args:     []any{[]byte{0x0a, 0xff}, ts},
+			expected: "INSERT INTO t (b, at) VALUES (X'0aff', '2024-03-01 12:30:00.5')",
+		},
+		{
+			name:     "postgres numbered",
+			driver:   sqlx.PostgreSQL,
+			query:    `SELECT '$1', "$2" FROM t WHERE a = $2 AND b = $1 AND c = $1`,
+			args:     []any{"x", &id},
+			expected: `SELECT '$1', "$2" FROM t WHERE a = 7 AND b = 'x' AND c = 'x'`,
+		},
+		{
+			name:     "postgres bytes and time",
+			driver:   sqlx.PostgreSQL,
+			query:    "SELECT $1, $2",
+			args:     []any{[]byte("hi"), ts},
+			expected: `SELECT '\x6869', '2024-03-01 12:30:00.5+02:00'`,
+		},
+		{
+			name:     "sqlite booleans",
+			driver:   sqlx.SQLite,
+			query:    "SELECT ? -- ?\n, ?",
+			args:     []any{false, []byte{1}},
+			expected: "SELECT 0 -- ?\n, X'01'",
+		},
+		{
+			name:     "sqlserver",
+			driver:   sqlx.SQLServer,
+			query:    "SELECT @p1, @p2, @p3",
+			args:     []any{"é", []byte{0xab}, sql.NullInt64{}},
+			expected: "SELECT N'é', 0xab, NULL",
+		},
+		{
+			name:     "missing arguments",
+			driver:   sqlx.PostgreSQL,
+			query:    "SELECT $1, $2",
+			args:     []any{1},
+			expected: "SELECT 1, $2",
+		},
+		{
+			name:     "valuer",
+			driver:   sqlx.MySQL,
+			query:    "SELECT ?",
+			args:     []any{sql.NullString{String: "v", Valid: true}},
+			expected: "SELECT 'v'",
+		},
+		{
+			name:     "non driver value",
+			driver:   sqlx.MySQL,
+			query:    "SELECT ?",
+			args:     []any{[]int{1, 2}},
+			expected: "SELECT '[1 2]'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sqlx.Interpolate(tt.driver, tt.query, tt.args)
+			if got != tt.expected {
+				t.Errorf("Interpolate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrintfLogger(t *testing.T) {
+	var lines []string
+	logger := sqlx.PrintfLogger(sqlx.PostgreSQL, func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+
+	logger.LogQuery(context.Background(), "SELECT $1", []any{"a"}, time.Millisecond, nil)
+	logger.LogQuery(context.Background(), "SELECT $1", []any{1}, time.Millisecond, errors.New("boom"))
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	if lines[0] != "sql: SELECT 'a' [1ms]" {
+		t.Errorf("unexpected log line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ": boom") {
+		t.Errorf("unexpected error log line %q", lines[1])
+	}
+}
+
+func TestQueryLogging(t *testing.T) {
+	var lines []string
+	logger := sqlx.PrintfLogger(sqlx.MySQL, func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"UPDATE `users` SET `name` = ? WHERE `id` = ?": {rowsAffected: 1},
+	}, func(c sqlx.Config) sqlx.Config { return c.WithLogger(logger) })
+	ctx := context.Background()
+
+	if _, err := db.Exec(ctx, "UPDATE `users` SET `name` = ? WHERE `id` = ?", "Alice", 1); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	var queryErr *sqlx.QueryError
+	if _, err := db.Query(ctx, "SELECT * FROM `missing` WHERE `id` = ?", 2); err == nil || errors.As(err, &queryErr) {
+		t.Errorf("Query error = %#v, want the driver error without QueryInErrors", err)
+	}
+
+	tx, err := db.Begin(ctx, nil)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(ctx, "DELETE FROM `missing`"); err == nil {
+		t.Error("Tx.Exec succeeded, want an error")
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d: %q", len(lines), lines)
+	}
+	if !strings.HasPrefix(lines[0], "sql: UPDATE `users` SET `name` = 'Alice' WHERE `id` = 1 [") {
+		t.Errorf("unexpected log line %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "unexpected query") || !strings.Contains(lines[2], "DELETE FROM `missing`") {
+		t.Errorf("unexpected error log lines %q", lines[1:])
+	}
+}
+
+func TestQueryInErrors(t *testing.T) {
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{}, func(c sqlx.Config) sqlx.Config {
+		return c.WithQueryInErrors(true)
+	})
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, "UPDATE `connections` SET `token` = ? WHERE `id` = ?", "s3cret", 1062)
+	var queryErr *sqlx.QueryError
+	if !errors.As(err, &queryErr) || queryErr.Query != "UPDATE `connections` SET `token` = ? WHERE `id` = ?" {
+		t.Fatalf("Exec error = %v, want a QueryError with the query", err)
+	}
+	if strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("error %q contains an argument value", err)
+	}
+
+	tx, err := db.Begin(ctx, nil)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Query(ctx, "SELECT 1"); !errors.As(err, &queryErr) || !strings.HasSuffix(err.Error(), "[query: SELECT 1]") {
+		t.Errorf("Tx.Query error = %v, want a QueryError", err)
+	}
+
+	// Classifiers look at the driver error only, not the query text.
+	classified := &sqlx.QueryError{Query: "INSERT INTO connections (id) VALUES (1062) -- unique constraint, locked", Err: errors.New("boom")}
+	if sqlx.IsDuplicateError(classified) || sqlx.ShouldRetry(classified) || sqlx.IsForeignKeyError(classified) {
+		t.Error("classifiers matched the query text of a QueryError")
+	}
+	if !sqlx.IsDuplicateError(&sqlx.QueryError{Query: "INSERT", Err: errors.New("Error 1062: Duplicate entry")}) {
+		t.Error("IsDuplicateError() = false for a wrapped duplicate error")
+	}
+}
+
+func TestInterpolateUnknownDriver(t *testing.T) {
+	query := "SELECT * FROM t WHERE a = ? AND b = $1"
+	if got := sqlx.Interpolate(sqlx.Driver("unknown"), query, []any{1}); got != query {
+		t.Errorf("Interpolate() = %q, want the query unchanged", got)
+	}
+}
@@ -0,0 +1,346 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keyset paginates a SelectBuilder by ordered key columns instead of
+// LIMIT/OFFSET. Each page continues from a cursor token produced from the
+// last (or first) row of the previous page, so deep pages stay fast and rows
+// inserted meanwhile do not shift page boundaries.
+//
+// The key columns must identify rows uniquely when taken together (end with
+// the primary key) and must not contain NULLs. Cursor tokens record the type
+// of each key value: times come back as time.Time and []byte as []byte, while
+// other values come back by kind as bool, int64, uint64, float64 or string.
+// driver.Valuer values are stored as the value they return.
+//
+//	ks := sqlx.NewKeyset(50, "created_at DESC", "id DESC").After(token)
+//	query, args, err := db.NewSelectBuilder().From("events").Keyset(ks).ToSQL()
+//	...
+//	next, err := ks.NextCursor(rows[len(rows)-1])
+type Keyset struct {
+	columns []string
+	limit   int
+	cursor  string
+}
+
+// keysetCursor is the decoded form of a cursor token.
+type keysetCursor struct {
+	Backward bool
+	Values   []any
+}
+
+// cursorToken is the JSON form of a keysetCursor.
+type cursorToken struct {
+	Backward bool        `json:"b,omitempty"`
+	Keys     []cursorKey `json:"k"`
+}
+
+// cursorKey is a key value in a cursor token, encoded as text and tagged with
+// its type so that decoding restores it.
+type cursorKey struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// Types of cursorKey values.
+const (
+	cursorNull   = "null"
+	cursorBool   = "bool"
+	cursorInt    = "int"
+	cursorUint   = "uint"
+	cursorFloat  = "float"
+	cursorString = "string"
+	cursorBytes  = "bytes"
+	cursorTime   = "time"
+)
+
+// NewKeyset creates a Keyset returning up to limit rows per page, ordered by
+// the given key columns. Each entry is a column name optionally followed by
+// ASC or DESC, as for SelectBuilder.OrderBy.
+func NewKeyset(limit int, columns ...string) *Keyset {
+	return &Keyset{columns: columns, limit: limit}
+}
+
+// After continues from a cursor token returned by NextCursor or PrevCursor.
+// An empty token selects the first page.
+func (k *Keyset) After(cursor string) *Keyset {
+	k.cursor = cursor
+	return k
+}
+
+// Backward reports whether the current cursor pages backwards. Rows of a
+// backward page are fetched in reverse key order; reverse them before display.
+func (k *Keyset) Backward() bool {
+	cursor, err := decodeCursor(k.cursor)
+	return err == nil && cursor.Backward
+}
+
+// NextCursor returns the token for the page after the one ending with row.
+// row is a Map or a struct read with the default Binder tag rules, and must
+// hold a value for every key column.
+func (k *Keyset) NextCursor(row any) (string, error) {
+	return k.encodeCursor(row, false)
+}
+
+// PrevCursor returns the token for the page before the one starting with row.
+func (k *Keyset) PrevCursor(row any) (string, error) {
+	return k.encodeCursor(row, true)
+}
+
+// encodeCursor builds a cursor token from the key column values of row.
+func (k *Keyset) encodeCursor(row any, backward bool) (string, error) {
+	values, err := namedValues(row)
+	if err != nil {
+		return "", err
+	}
+
+	token := cursorToken{Backward: backward, Keys: make([]cursorKey, len(k.columns))}
+	for i, entry := range k.columns {
+		column, _, err := parseKeysetColumn(entry)
+		if err != nil {
+			return "", err
+		}
+
+		value, ok := values[column]
+		if !ok {
+			return "", fmt.Errorf("%w: row has no value for key column %q", ErrInvalidArguments, column)
+		}
+		if token.Keys[i], err = encodeCursorKey(value); err != nil {
+			return "", fmt.Errorf("key column %q: %w", column, err)
+		}
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("%w: encode cursor: %v", ErrInvalidArguments, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// build renders the keyset predicate (empty on the first page) and the
+// ORDER BY entries for the builder.
+func (k *Keyset) build() (Condition, []string, error) {
+	if len(k.columns) == 0 {
+		return nil, nil, fmt.Errorf("%w: keyset requires at least one key column", ErrInvalidArguments)
+	}
+	if k.limit <= 0 {
+		return nil, nil, fmt.Errorf("%w: keyset limit must be > 0", ErrInvalidArguments)
+	}
+
+	cursor, err := decodeCursor(k.cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cursor.Values != nil && len(cursor.Values) != len(k.columns) {
+		return nil, nil, fmt.Errorf("%w: cursor has %d values for %d key columns", ErrInvalidArguments, len(cursor.Values), len(k.columns))
+	}
+
+	columns := make([]string, len(k.columns))
+	descending := make([]bool, len(k.columns))
+	orderBy := make([]string, len(k.columns))
+	for i, entry := range k.columns {
+		column, desc, err := parseKeysetColumn(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Paging backwards walks the index in the opposite direction.
+		if cursor.Backward {
+			desc = !desc
+		}
+
+		columns[i] = column
+		descending[i] = desc
+		orderBy[i] = column + " ASC"
+		if desc {
+			orderBy[i] = column + " DESC"
+		}
+	}
+
+	if cursor.Values == nil {
+		return nil, orderBy, nil
+	}
+	return &keysetCondition{columns: columns, descending: descending, values: cursor.Values}, orderBy, nil
+}
+
+// keysetCondition selects the rows after a cursor position.
+type keysetCondition struct {
+	columns    []string
+	descending []bool
+	values     []any
+}
+
+// ToSQL implements Condition. Dialects with FeatureRowValueSeek get a row
+// value comparison when all key columns sort the same way; otherwise the
+// comparison is expanded to a > ? OR (a = ? AND b > ?) ..., which every
+// driver plans well.
+func (c *keysetCondition) ToSQL(driver Driver) (string, []any, error) {
+	escaped := make([]string, len(c.columns))
+	uniform := true
+	for i, column := range c.columns {
+		var err error
+		if escaped[i], err = EscapeColumnName(driver, column); err != nil {
+			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
+		}
+		if c.descending[i] != c.descending[0] {
+			uniform = false
+		}
+	}
+
+	if uniform && supports(driver, FeatureRowValueSeek) {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(escaped, ", "), keysetOp(c.descending[0]), Placeholders(len(c.values))), c.values, nil
+	}
+
+	terms := make([]string, len(escaped))
+	var args []any
+	for i := range escaped {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, escaped[j]+" = ?")
+			args = append(args, c.values[j])
+		}
+		parts = append(parts, escaped[i]+" "+keysetOp(c.descending[i])+" ?")
+		args = append(args, c.values[i])
+
+		terms[i] = parts[0]
+		if len(parts) > 1 {
+			terms[i] = "(" + strings.Join(parts, " AND ") + ")"
+		}
+	}
+	return strings.Join(terms, " OR "), args, nil
+}
+
+// keysetOp returns the comparison that moves past a cursor value.
+func keysetOp(descending bool) string {
+	if descending {
+		return "<"
+	}
+	return ">"
+}
+
+// parseKeysetColumn splits "column [ASC|DESC]" into its name and direction.
+func parseKeysetColumn(entry string) (string, bool, error) {
+	fields := strings.Fields(entry)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", false, fmt.Errorf("%w: invalid key column %q", ErrInvalidArguments, entry)
+	}
+	if len(fields) == 1 {
+		return fields[0], false, nil
+	}
+
+	switch strings.ToUpper(fields[1]) {
+	case "ASC":
+		return fields[0], false, nil
+	case "DESC":
+		return fields[0], true, nil
+	}
+	return "", false, fmt.Errorf("%w: invalid sort direction %q", ErrInvalidArguments, fields[1])
+}
+
+// decodeCursor parses a cursor token. An empty token yields an empty cursor.
+func decodeCursor(token string) (keysetCursor, error) {
+	var cursor keysetCursor
+	if token == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: malformed cursor", ErrInvalidArguments)
+	}
+
+	var wire cursorToken
+	if err := json.Unmarshal(data, &wire); err != nil || wire.Keys == nil {
+		return cursor, fmt.Errorf("%w: malformed cursor", ErrInvalidArguments)
+	}
+
+	cursor.Backward = wire.Backward
+	cursor.Values = make([]any, len(wire.Keys))
+	for i, key := range wire.Keys {
+		if cursor.Values[i], err = decodeCursorKey(key); err != nil {
+			return keysetCursor{}, fmt.Errorf("%w: malformed cursor: %v", ErrInvalidArguments, err)
+		}
+	}
+	return cursor, nil
+}
+
+// encodeCursorKey encodes a key value with its type.
+func encodeCursorKey(value any) (cursorKey, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return cursorKey{}, err
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return cursorKey{Type: cursorNull}, nil
+	case time.Time:
+		return cursorKey{Type: cursorTime, Value: v.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return cursorKey{Type: cursorBytes, Value: base64.StdEncoding.EncodeToString(v)}, nil
+	}
+
+	val := reflect.ValueOf(value)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return cursorKey{Type: cursorNull}, nil
+		}
+		val = val.Elem()
+	}
+	if t, ok := val.Interface().(time.Time); ok {
+		return encodeCursorKey(t)
+	}
+
+	switch val.Kind() {
+	case reflect.Bool:
+		return cursorKey{Type: cursorBool, Value: strconv.FormatBool(val.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorKey{Type: cursorInt, Value: strconv.FormatInt(val.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorKey{Type: cursorUint, Value: strconv.FormatUint(val.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorKey{Type: cursorFloat, Value: strconv.FormatFloat(val.Float(), 'g', -1, 64)}, nil
+	case reflect.String:
+		return cursorKey{Type: cursorString, Value: val.String()}, nil
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			return cursorKey{Type: cursorBytes, Value: base64.StdEncoding.EncodeToString(val.Bytes())}, nil
+		}
+	}
+	return cursorKey{}, fmt.Errorf("%w: unsupported cursor value type %T", ErrInvalidArguments, value)
+}
+
+// decodeCursorKey restores a key value encoded by encodeCursorKey.
+func decodeCursorKey(key cursorKey) (any, error) {
+	switch key.Type {
+	case cursorNull:
+		return nil, nil
+	case cursorBool:
+		return strconv.ParseBool(key.Value)
+	case cursorInt:
+		return strconv.ParseInt(key.Value, 10, 64)
+	case cursorUint:
+		return strconv.ParseUint(key.Value, 10, 64)
+	case cursorFloat:
+		return strconv.ParseFloat(key.Value, 64)
+	case cursorString:
+		return key.Value, nil
+	case cursorBytes:
+		return base64.StdEncoding.DecodeString(key.Value)
+	case cursorTime:
+		return time.Parse(time.RFC3339Nano, key.Value)
+	}
+	return nil, fmt.Errorf("unknown value type %q", key.Type)
+}
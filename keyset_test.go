@@ -0,0 +1,167 @@
+package sqlx_test
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestKeysetFirstPage(t *testing.T) {
+	ks := sqlx.NewKeyset(20, "created_at DESC", "id DESC")
+	query, args, err := sqlx.NewSelectBuilder(sqlx.MySQL).From("events").Where(sqlx.Col("kind").Eq("click")).Keyset(ks).ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	expected := "SELECT * FROM `events` WHERE `kind` = ? ORDER BY `created_at` DESC, `id` DESC LIMIT 20"
+	if query != expected {
+		t.Errorf("ToSQL() = %q, want %q", query, expected)
+	}
+	if len(args) != 1 {
+		t.Errorf("ToSQL() returned %d args, want 1", len(args))
+	}
+}
+
+func TestKeysetCursor(t *testing.T) {
+	type event struct {
+		ID    int64  `db:"id"`
+		Score int    `db:"score"`
+		Name  string `db:"name"`
+	}
+
+	ks := sqlx.NewKeyset(10, "score DESC", "id")
+	next, err := ks.NextCursor(&event{ID: 42, Score: 7, Name: "x"})
+	if err != nil {
+		t.Fatalf("NextCursor() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		expected string
+	}{
+		{
+			name:     "mysql expanded form",
+			driver:   sqlx.MySQL,
+			expected: "SELECT * FROM `events` WHERE `kind` = ? AND (`score` < ? OR (`score` = ? AND `id` > ?)) ORDER BY `score` DESC, `id` ASC LIMIT 10",
+		},
+		{
+			name:     "postgres mixed directions",
+			driver:   sqlx.PostgreSQL,
+			expected: `SELECT * FROM "events" WHERE "kind" = $1 AND ("score" < $2 OR ("score" = $3 AND "id" > $4)) ORDER BY "score" DESC, "id" ASC LIMIT 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := sqlx.NewSelectBuilder(tt.driver).From("events").
+				Where(sqlx.Col("kind").Eq("click")).
+				Keyset(sqlx.NewKeyset(10, "score DESC", "id").After(next)).
+				ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("ToSQL() = %q, want %q", query, tt.expected)
+			}
+			if want := []any{"click", int64(7), int64(7), int64(42)}; !reflect.DeepEqual(args, want) {
+				t.Errorf("ToSQL() args = %v, want %v", args, want)
+			}
+		})
+	}
+}
+
+func TestKeysetRowValueAndBackward(t *testing.T) {
+	ks := sqlx.NewKeyset(5, "created_at", "id")
+	prev, err := ks.PrevCursor(sqlx.Map{"created_at": "2024-01-01", "id": 3})
+	if err != nil {
+		t.Fatalf("PrevCursor() error = %v", err)
+	}
+
+	ks = sqlx.NewKeyset(5, "created_at", "id").After(prev)
+	if !ks.Backward() {
+		t.Error("Backward() = false for a PrevCursor token")
+	}
+
+	query, _, err := sqlx.NewSelectBuilder(sqlx.PostgreSQL).From("events").Keyset(ks).ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	expected := `SELECT * FROM "events" WHERE ("created_at", "id") < ($1, $2) ORDER BY "created_at" DESC, "id" DESC LIMIT 5`
+	if query != expected {
+		t.Errorf("ToSQL() = %q, want %q", query, expected)
+	}
+}
+
+func TestKeysetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *sqlx.SelectBuilder
+	}{
+		{"malformed cursor", sqlx.NewSelectBuilder(sqlx.MySQL).From("t").Keyset(sqlx.NewKeyset(10, "id").After("not-a-cursor"))},
+		{"no key columns", sqlx.NewSelectBuilder(sqlx.MySQL).From("t").Keyset(sqlx.NewKeyset(10))},
+		{"zero limit", sqlx.NewSelectBuilder(sqlx.MySQL).From("t").Keyset(sqlx.NewKeyset(0, "id"))},
+		{"combined with order by", sqlx.NewSelectBuilder(sqlx.MySQL).From("t").OrderBy("id").Keyset(sqlx.NewKeyset(10, "id"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.ToSQL()
+			if !errors.Is(err, sqlx.ErrInvalidArguments) {
+				t.Errorf("ToSQL() error = %v, want %v", err, sqlx.ErrInvalidArguments)
+			}
+		})
+	}
+
+	_, err := sqlx.NewKeyset(10, "id").NextCursor(sqlx.Map{"name": "x"})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("NextCursor() error = %v, want %v", err, sqlx.ErrInvalidArguments)
+	}
+}
+
+func TestKeysetCursorKeepsTypes(t *testing.T) {
+	created := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("", 3600))
+	row := sqlx.Map{
+		"created_at": created,
+		"hash":       []byte{0x00, 0xff},
+		"seq":        uint64(18446744073709551615),
+		"ratio":      0.1,
+		"ok":         true,
+		"ref":        sql.NullInt64{Int64: 5, Valid: true},
+		"name":       "",
+	}
+	columns := []string{"created_at", "hash", "seq", "ratio", "ok", "ref", "name"}
+
+	next, err := sqlx.NewKeyset(10, columns...).NextCursor(row)
+	if err != nil {
+		t.Fatalf("NextCursor() error = %v", err)
+	}
+
+	_, args, err := sqlx.NewSelectBuilder(sqlx.SQLite).From("events").
+		Keyset(sqlx.NewKeyset(10, columns...).After(next)).ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	want := []any{created, []byte{0x00, 0xff}, uint64(18446744073709551615), 0.1, true, int64(5), ""}
+	if len(args) != len(want)*(len(want)+1)/2 {
+		t.Fatalf("ToSQL() returned %d args", len(args))
+	}
+	// The expanded form binds each key value again in every later term.
+	got := make([]any, 0, len(want))
+	for i, n := 0, 1; i < len(args); i, n = i+n, n+1 {
+		got = append(got, args[i+n-1])
+	}
+	if at, ok := got[0].(time.Time); !ok || !at.Equal(created) {
+		t.Errorf("created_at = %#v, want time.Time %v", got[0], created)
+	}
+	if !reflect.DeepEqual(got[1:], want[1:]) {
+		t.Errorf("cursor values = %#v, want %#v", got[1:], want[1:])
+	}
+
+	if _, err := sqlx.NewKeyset(10, "id").NextCursor(sqlx.Map{"id": struct{}{}}); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("NextCursor() with unsupported type error = %v, want ErrInvalidArguments", err)
+	}
+}
@@ -0,0 +1,348 @@
+package sqlx
+
+import (
+	"strings"
+)
+
+// tokenKind classifies a lexical token of a SQL statement.
+type tokenKind int
+
+const (
+	tokenSpace   tokenKind = iota // whitespace
+	tokenComment                  // -- line, # line (MySQL) or /* block */ comment
+	tokenString                   // string literal, including dollar-quoted strings
+	tokenIdent                    // quoted identifier
+	tokenWord                     // keyword, bare identifier, number or $N parameter
+	tokenSymbol                   // any other single byte, e.g. ( ) , ; ?
+)
+
+// token is a lexical token with its position and parenthesis depth.
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+	depth int
+}
+
+// isCode reports whether the token takes part in the statement's syntax.
+func (t token) isCode() bool {
+	return t.kind != tokenSpace && t.kind != tokenComment
+}
+
+// isWord reports whether the token is the given keyword, ignoring case.
+func (t token) isWord(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// lexRules are the quoting and comment rules of a dialect.
+type lexRules struct {
+	backslashEscapes    bool
+	doubleQuotedStrings bool
+	hashComments        bool
+	dollarQuotes        bool
+	nestedComments      bool
+	bracketIdentifiers  bool
+}
+
+// lexRulesFor returns the lexical rules of the driver's dialect. Unregistered
+// drivers get portable rules: '...' strings, "..." and `...` identifiers, and
+// -- and /* */ comments.
+func lexRulesFor(driver Driver) lexRules {
+	return lexRulesOf(lookupDialect(driver))
+}
+
+// lexRulesOf returns the lexical rules of a dialect, or the portable rules
+// for a nil dialect.
+func lexRulesOf(dialect Dialect) lexRules {
+	if dialect == nil {
+		return lexRules{}
+	}
+
+	return lexRules{
+		backslashEscapes:    dialect.Supports(FeatureBackslashEscapes),
+		doubleQuotedStrings: dialect.Supports(FeatureDoubleQuotedStrings),
+		hashComments:        dialect.Supports(FeatureHashComments),
+		dollarQuotes:        dialect.Supports(FeatureDollarQuotes),
+		nestedComments:      dialect.Supports(FeatureNestedComments),
+		bracketIdentifiers:  dialect.Supports(FeatureBracketIdentifiers),
+	}
+}
+
+// tokenize splits a SQL statement into tokens following the given quoting
+// and comment rules.
+// Unterminated literals and comments run to the end of the query.
+func tokenize(rules lexRules, query string) []token {
+	var tokens []token
+	depth := 0
+
+	for i := 0; i < len(query); {
+		kind, end := scanToken(rules, query, i)
+
+		if kind == tokenSymbol && query[i] == ')' && depth > 0 {
+			depth--
+		}
+		tokens = append(tokens, token{kind: kind, text: query[i:end], start: i, end: end, depth: depth})
+		if kind == tokenSymbol && query[i] == '(' {
+			depth++
+		}
+
+		i = end
+	}
+
+	return tokens
+}
+
+// scanToken returns the kind and end offset of the token starting at i.
+func scanToken(rules lexRules, query string, i int) (tokenKind, int) {
+	c := query[i]
+
+	switch {
+	case isSpace(c):
+		end := i + 1
+		for end < len(query) && isSpace(query[end]) {
+			end++
+		}
+		return tokenSpace, end
+
+	case c == '-' && strings.HasPrefix(query[i:], "--"),
+		c == '#' && rules.hashComments:
+		end := strings.IndexByte(query[i:], '\n')
+		if end == -1 {
+			return tokenComment, len(query)
+		}
+		return tokenComment, i + end + 1
+
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		return tokenComment, scanBlockComment(query, i, rules.nestedComments)
+
+	case c == '\'':
+		return tokenString, scanQuoted(query, i, rules.backslashEscapes)
+
+	case c == '"':
+		if rules.doubleQuotedStrings {
+			return tokenString, scanQuoted(query, i, rules.backslashEscapes)
+		}
+		return tokenIdent, scanQuoted(query, i, false)
+
+	case c == '`':
+		return tokenIdent, scanQuoted(query, i, false)
+
+	case c == '[' && rules.bracketIdentifiers:
+		return tokenIdent, scanBracketed(query, i)
+
+	case c == '$' && rules.dollarQuotes:
+		if end, ok := scanDollarQuoted(query, i); ok {
+			return tokenString, end
+		}
+		end := i + 1
+		for end < len(query) && isWordChar(query[end]) {
+			end++
+		}
+		return tokenWord, end
+
+	case isWordChar(c):
+		// PostgreSQL escape strings (E'...') honour backslash escapes.
+		if (c == 'E' || c == 'e') && rules.dollarQuotes && i+1 < len(query) && query[i+1] == '\'' &&
+			(i == 0 || !isWordChar(query[i-1])) {
+			return tokenString, scanQuoted(query, i+1, true)
+		}
+		end := i + 1
+		for end < len(query) && (isWordChar(query[end]) || query[end] == '$') {
+			end++
+		}
+		return tokenWord, end
+	}
+
+	return tokenSymbol, i + 1
+}
+
+// scanQuoted returns the offset just past the quoted text starting at i.
+// A doubled quote character stands for itself; with backslashes enabled, a
+// backslash escapes the following byte.
+func scanQuoted(query string, i int, backslashes bool) int {
+	quote := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if backslashes {
+				j++
+			}
+		case quote:
+			if j+1 < len(query) && query[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(query)
+}
+
+// scanBracketed returns the offset just past the [...] identifier at i.
+// A doubled closing bracket stands for itself.
+func scanBracketed(query string, i int) int {
+	for j := i + 1; j < len(query); j++ {
+		if query[j] != ']' {
+			continue
+		}
+		if j+1 < len(query) && query[j+1] == ']' {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(query)
+}
+
+// scanBlockComment returns the offset just past the /* */ comment at i,
+// honouring nested comments if enabled.
+func scanBlockComment(query string, i int, nested bool) int {
+	nesting := 0
+	for j := i; j+1 < len(query); j++ {
+		switch {
+		case query[j] == '/' && query[j+1] == '*':
+			if nesting == 0 || nested {
+				nesting++
+			}
+			j++
+		case query[j] == '*' && query[j+1] == '/':
+			nesting--
+			j++
+			if nesting == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(query)
+}
+
+// scanDollarQuoted scans a PostgreSQL dollar-quoted string ($$...$$ or
+// $tag$...$tag$) starting at i. It reports false if no such string starts there.
+func scanDollarQuoted(query string, i int) (int, bool) {
+	j := i + 1
+	for j < len(query) && isWordChar(query[j]) && !(j == i+1 && query[j] >= '0' && query[j] <= '9') {
+		j++
+	}
+	if j >= len(query) || query[j] != '$' {
+		return 0, false
+	}
+
+	tag := query[i : j+1]
+	end := strings.Index(query[j+1:], tag)
+	if end == -1 {
+		return len(query), true
+	}
+	return j + 1 + end + len(tag), true
+}
+
+// isSpace reports whether c is SQL whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// isWordChar reports whether c may appear in a bare word. Bytes of
+// multi-byte UTF-8 characters count as word characters.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// statement is a tokenized SQL statement with helpers for locating its
+// top-level clauses.
+type statement struct {
+	query  string
+	tokens []token
+}
+
+// parseStatement tokenizes a query for clause lookups.
+func parseStatement(rules lexRules, query string) *statement {
+	return &statement{query: query, tokens: tokenize(rules, query)}
+}
+
+// next returns the index of the first code token at or after i, or -1.
+func (s *statement) next(i int) int {
+	for ; i < len(s.tokens); i++ {
+		if s.tokens[i].isCode() {
+			return i
+		}
+	}
+	return -1
+}
+
+// find returns the index of the first top-level occurrence of a keyword
+// sequence such as "ORDER BY" at or after token i, or -1. Keywords inside
+// parentheses, literals and comments are ignored.
+func (s *statement) find(i int, keywords ...string) int {
+	for ; i < len(s.tokens); i++ {
+		if s.tokens[i].depth == 0 && s.matches(i, keywords) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches reports whether the code tokens starting at i spell keywords.
+func (s *statement) matches(i int, keywords []string) bool {
+	for _, keyword := range keywords {
+		if i < 0 || !s.tokens[i].isWord(keyword) {
+			return false
+		}
+		i = s.next(i + 1)
+	}
+	return true
+}
+
+// firstIndex returns the smallest non-negative index among the candidates, or -1.
+func firstIndex(indexes ...int) int {
+	result := -1
+	for _, index := range indexes {
+		if index >= 0 && (result == -1 || index < result) {
+			result = index
+		}
+	}
+	return result
+}
+
+// offset returns the byte offset of token i, or the query length when i is -1.
+func (s *statement) offset(i int) int {
+	if i < 0 {
+		return len(s.query)
+	}
+	return s.tokens[i].start
+}
+
+// selectStart returns the index of the leading SELECT keyword, or -1 when the
+// statement is not a SELECT.
+func (s *statement) selectStart() int {
+	i := s.next(0)
+	if i < 0 || s.tokens[i].depth != 0 || !s.tokens[i].isWord("SELECT") {
+		return -1
+	}
+	return i
+}
+
+// tail returns the index of the first top-level clause that ends the query
+// body: ORDER BY, LIMIT, OFFSET, FETCH, a locking clause or a semicolon.
+func (s *statement) tail(i int) int {
+	return firstIndex(
+		s.find(i, "ORDER", "BY"),
+		s.find(i, "LIMIT"),
+		s.find(i, "OFFSET"),
+		s.find(i, "FETCH"),
+		s.find(i, "FOR"),
+		s.find(i, "LOCK", "IN"),
+		s.semicolon(i),
+	)
+}
+
+// semicolon returns the index of the first top-level semicolon at or after
+// token i, or -1.
+func (s *statement) semicolon(i int) int {
+	for ; i < len(s.tokens); i++ {
+		if t := s.tokens[i]; t.depth == 0 && t.kind == tokenSymbol && t.text == ";" {
+			return i
+		}
+	}
+	return -1
+}
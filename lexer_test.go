@@ -0,0 +1,129 @@
+package sqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestCountQueryWithDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		input    string
+		expected string
+	}{
+		{
+			name:     "mysql backslash escape",
+			driver:   sqlx.MySQL,
+			input:    `SELECT * FROM notes WHERE body = 'it\'s ORDER BY' LIMIT 10`,
+			expected: `SELECT COUNT(*) FROM notes WHERE body = 'it\'s ORDER BY'`,
+		},
+		{
+			name:     "postgres dollar quote and lock",
+			driver:   sqlx.PostgreSQL,
+			input:    "SELECT id FROM jobs WHERE tag = $$ LIMIT $$ FOR UPDATE SKIP LOCKED",
+			expected: "SELECT COUNT(*) FROM jobs WHERE tag = $$ LIMIT $$",
+		},
+		{
+			name:     "quoted identifier and comment",
+			driver:   sqlx.SQLite,
+			input:    "/* report */ SELECT \"from\" FROM t -- LIMIT 1\nWHERE x > 1 ORDER BY \"from\"",
+			expected: "SELECT COUNT(*) FROM t -- LIMIT 1\nWHERE x > 1",
+		},
+		{
+			name:     "union is wrapped",
+			driver:   sqlx.MySQL,
+			input:    "SELECT id FROM a UNION SELECT id FROM b ORDER BY id;",
+			expected: "SELECT COUNT(*) FROM (SELECT id FROM a UNION SELECT id FROM b) t",
+		},
+		{
+			name:     "cte keeps the with clause in front",
+			driver:   sqlx.PostgreSQL,
+			input:    "WITH recent AS (SELECT * FROM orders ORDER BY id LIMIT 10) SELECT * FROM recent",
+			expected: "WITH recent AS (SELECT * FROM orders ORDER BY id LIMIT 10) SELECT COUNT(*) FROM (SELECT * FROM recent) t",
+		},
+		{
+			name:     "sql server cte",
+			driver:   sqlx.SQLServer,
+			input:    "WITH a AS (SELECT id FROM [orders]), b AS (SELECT id FROM a) SELECT id FROM b ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+			expected: "WITH a AS (SELECT id FROM [orders]), b AS (SELECT id FROM a) SELECT COUNT(*) FROM (SELECT id FROM b) t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sqlx.CountQueryWithDriver(tt.driver, tt.input)
+			if err != nil {
+				t.Fatalf("CountQueryWithDriver() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("CountQueryWithDriver() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := sqlx.CountQueryWithDriver(sqlx.MySQL, "UPDATE users SET x = 1"); !errors.Is(err, sqlx.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for non-SELECT, got %v", err)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"SELECT email FROM users", "SELECT DISTINCT email FROM users"},
+		{"-- SELECT\nselect email FROM users", "-- SELECT\nselect DISTINCT email FROM users"},
+		{"SELECT ALL email FROM users", "SELECT DISTINCT email FROM users"},
+		{"SELECT DISTINCT email FROM users", "SELECT DISTINCT email FROM users"},
+		{"UPDATE users SET note = 'SELECT'", "UPDATE users SET note = 'SELECT'"},
+	}
+
+	for _, tt := range tests {
+		if result := sqlx.Distinct(tt.input); result != tt.expected {
+			t.Errorf("Distinct(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestPaginateWithDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		input    string
+		expected string
+	}{
+		{
+			name:     "append",
+			driver:   sqlx.MySQL,
+			input:    "SELECT * FROM users ORDER BY id",
+			expected: "SELECT * FROM users ORDER BY id LIMIT 10 OFFSET 20",
+		},
+		{
+			name:     "replace existing limit",
+			driver:   sqlx.PostgreSQL,
+			input:    "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders LIMIT 5) LIMIT 100 OFFSET 3",
+			expected: "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders LIMIT 5) LIMIT 10 OFFSET 20",
+		},
+		{
+			name:     "before locking clause and semicolon",
+			driver:   sqlx.MySQL,
+			input:    "SELECT * FROM jobs FOR UPDATE;",
+			expected: "SELECT * FROM jobs LIMIT 10 OFFSET 20 FOR UPDATE;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sqlx.PaginateWithDriver(tt.driver, tt.input, 3, 10)
+			if err != nil {
+				t.Fatalf("PaginateWithDriver() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("PaginateWithDriver() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package sqlx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LockStrength is the kind of row lock taken by a locking SELECT.
+type LockStrength int
+
+const (
+	// LockForUpdate takes exclusive row locks (FOR UPDATE).
+	LockForUpdate LockStrength = iota + 1
+
+	// LockForNoKeyUpdate takes exclusive locks that do not block foreign key
+	// checks (FOR NO KEY UPDATE). PostgreSQL only.
+	LockForNoKeyUpdate
+
+	// LockForShare takes shared row locks (FOR SHARE).
+	LockForShare
+
+	// LockForKeyShare takes shared locks that only block key changes
+	// (FOR KEY SHARE). PostgreSQL only.
+	LockForKeyShare
+)
+
+// LockWait is what a locking SELECT does when a row is already locked.
+type LockWait int
+
+const (
+	// LockWaitBlock waits for the conflicting lock to be released.
+	LockWaitBlock LockWait = iota
+
+	// LockNoWait fails immediately (NOWAIT).
+	LockNoWait
+
+	// LockSkipLocked skips rows that are already locked (SKIP LOCKED).
+	LockSkipLocked
+)
+
+// Lock describes the row locking clause of a SELECT, e.g.
+// ForUpdate().SkipLocked() for queue-style workers. Locking selects only
+// make sense inside a transaction, so WithLock is accepted by Tx.Select and
+// Tx.SelectOne, and SelectBuilder.Query refuses a locking builder on a DB.
+type Lock struct {
+	// Strength is the kind of lock to take.
+	Strength LockStrength
+
+	// Wait is the behaviour on already locked rows.
+	Wait LockWait
+
+	// Tables restricts locking to the given tables or aliases (OF ...).
+	// Empty means every table in the query.
+	Tables []string
+}
+
+// ForUpdate returns a FOR UPDATE lock.
+func ForUpdate() Lock {
+	return Lock{Strength: LockForUpdate}
+}
+
+// ForShare returns a FOR SHARE lock.
+func ForShare() Lock {
+	return Lock{Strength: LockForShare}
+}
+
+// NoWait returns a copy of the lock that fails instead of waiting.
+func (l Lock) NoWait() Lock {
+	l.Wait = LockNoWait
+	return l
+}
+
+// SkipLocked returns a copy of the lock that skips already locked rows.
+func (l Lock) SkipLocked() Lock {
+	l.Wait = LockSkipLocked
+	return l
+}
+
+// Of returns a copy of the lock restricted to the given tables or aliases.
+func (l Lock) Of(tables ...string) Lock {
+	l.Tables = append([]string(nil), tables...)
+	return l
+}
+
+// LockClauseWithDriver renders a row locking clause such as
+// "FOR UPDATE SKIP LOCKED" for the driver. Drivers without locking clauses,
+// such as SQLite, return ErrDriverNotSupported.
+func LockClauseWithDriver(driver Driver, lock Lock) (string, error) {
+	if lock.Strength < LockForUpdate || lock.Strength > LockForKeyShare {
+		return "", fmt.Errorf("%w: invalid lock strength %d", ErrInvalidArguments, lock.Strength)
+	}
+	if lock.Wait < LockWaitBlock || lock.Wait > LockSkipLocked {
+		return "", fmt.Errorf("%w: invalid lock wait policy %d", ErrInvalidArguments, lock.Wait)
+	}
+
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", err
+	}
+
+	tables := make([]string, len(lock.Tables))
+	for i, table := range lock.Tables {
+		escaped, err := EscapeTableName(driver, table)
+		if err != nil {
+			return "", fmt.Errorf("escape lock table %q: %w", table, err)
+		}
+		tables[i] = escaped
+	}
+
+	return dialect.Lock(lock.Strength, lock.Wait, tables)
+}
+
+// lockClause renders "FOR <strength> [OF tables] [NOWAIT | SKIP LOCKED]".
+func lockClause(strength LockStrength, wait LockWait, tables []string) string {
+	var builder strings.Builder
+
+	switch strength {
+	case LockForUpdate:
+		builder.WriteString("FOR UPDATE")
+	case LockForNoKeyUpdate:
+		builder.WriteString("FOR NO KEY UPDATE")
+	case LockForShare:
+		builder.WriteString("FOR SHARE")
+	case LockForKeyShare:
+		builder.WriteString("FOR KEY SHARE")
+	}
+
+	if len(tables) > 0 {
+		builder.WriteString(" OF " + strings.Join(tables, ", "))
+	}
+
+	switch wait {
+	case LockNoWait:
+		builder.WriteString(" NOWAIT")
+	case LockSkipLocked:
+		builder.WriteString(" SKIP LOCKED")
+	}
+
+	return builder.String()
+}
+
+// SelectOption configures Tx.Select and Tx.SelectOne.
+type SelectOption func(*selectOptions)
+
+// selectOptions holds the settings applied by SelectOption values.
+type selectOptions struct {
+	lock *Lock
+}
+
+// WithLock adds a row locking clause to the select. Only Tx accepts select
+// options, so a locking select always runs in a transaction; see DB.Begin.
+func WithLock(lock Lock) SelectOption {
+	return func(o *selectOptions) {
+		o.lock = &lock
+	}
+}
+
+// buildSelectWithDriver builds the SELECT used by DB.Select and Tx.Select.
+func buildSelectWithDriver(driver Driver, table string, columns []string, where Condition, options selectOptions) (string, []any, error) {
+	if table == "" {
+		return "", nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	// Use driver-specific escaping for enhanced security
+	escapedTable, err := EscapeTableName(driver, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
+	}
+
+	columnList, err := buildColumnListWithDriver(driver, columns)
+	if err != nil {
+		return "", nil, fmt.Errorf("build column list: %w", err)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", columnList, escapedTable)
+
+	whereClause, args, err := buildWhereClauseWithDriver(driver, where)
+	if err != nil {
+		return "", nil, fmt.Errorf("build where clause: %w", err)
+	}
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+
+	if options.lock != nil {
+		lock, err := LockClauseWithDriver(driver, *options.lock)
+		if err != nil {
+			return "", nil, err
+		}
+		query += " " + lock
+	}
+
+	return Rebind(driver, query), args, nil
+}
+
+// newSelectOptions applies the options in order.
+func newSelectOptions(opts []SelectOption) selectOptions {
+	var options selectOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
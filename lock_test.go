@@ -0,0 +1,91 @@
+package sqlx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestLockClauseWithDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		lock     sqlx.Lock
+		expected string
+		wantErr  error
+	}{
+		{"postgres for update", sqlx.PostgreSQL, sqlx.ForUpdate(), "FOR UPDATE", nil},
+		{"postgres skip locked", sqlx.PostgreSQL, sqlx.ForUpdate().SkipLocked(), "FOR UPDATE SKIP LOCKED", nil},
+		{"postgres key share nowait", sqlx.PostgreSQL, sqlx.Lock{Strength: sqlx.LockForKeyShare, Wait: sqlx.LockNoWait}, "FOR KEY SHARE NOWAIT", nil},
+		{"postgres of tables", sqlx.PostgreSQL, sqlx.ForShare().Of("j", "public.accounts"), `FOR SHARE OF "j", "public"."accounts"`, nil},
+		{"mysql for share nowait", sqlx.MySQL, sqlx.ForShare().NoWait(), "FOR SHARE NOWAIT", nil},
+		{"mysql of skip locked", sqlx.MySQL, sqlx.ForUpdate().Of("jobs").SkipLocked(), "FOR UPDATE OF `jobs` SKIP LOCKED", nil},
+		{"mysql no key update", sqlx.MySQL, sqlx.Lock{Strength: sqlx.LockForNoKeyUpdate}, "", sqlx.ErrDriverNotSupported},
+		{"sqlite", sqlx.SQLite, sqlx.ForUpdate(), "", sqlx.ErrDriverNotSupported},
+		{"sqlserver", sqlx.SQLServer, sqlx.ForUpdate(), "", sqlx.ErrDriverNotSupported},
+		{"missing strength", sqlx.PostgreSQL, sqlx.Lock{}, "", sqlx.ErrInvalidArguments},
+		{"invalid table", sqlx.PostgreSQL, sqlx.ForUpdate().Of("jobs; --"), "", sqlx.ErrInvalidIdentifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, err := sqlx.LockClauseWithDriver(tt.driver, tt.lock)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("LockClauseWithDriver() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LockClauseWithDriver() error = %v", err)
+			}
+			if clause != tt.expected {
+				t.Errorf("LockClauseWithDriver() = %q, want %q", clause, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderLock(t *testing.T) {
+	builder := sqlx.NewSelectBuilder(sqlx.PostgreSQL).
+		Columns("id").
+		From("jobs").
+		Where(sqlx.Col("state").Eq("queued")).
+		OrderBy("id").
+		Limit(10).
+		Lock(sqlx.ForUpdate().SkipLocked())
+
+	query, _, err := builder.ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	expected := `SELECT "id" FROM "jobs" WHERE "state" = $1 ORDER BY "id" ASC LIMIT 10 FOR UPDATE SKIP LOCKED`
+	if query != expected {
+		t.Errorf("ToSQL() = %q, want %q", query, expected)
+	}
+
+	if _, err := builder.Query(context.Background(), &sqlx.DB{}); !errors.Is(err, sqlx.ErrInvalidOperation) {
+		t.Errorf("Query() on DB error = %v, want ErrInvalidOperation", err)
+	}
+
+	_, _, err = sqlx.NewSelectBuilder(sqlx.SQLite).From("jobs").Lock(sqlx.ForUpdate()).ToSQL()
+	if !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("ToSQL() on SQLite error = %v, want ErrDriverNotSupported", err)
+	}
+}
+
+func TestTxSelectOneLockErrors(t *testing.T) {
+	db := openFakeSQLX(t, sqlx.SQLite, nil)
+	tx, err := db.Begin(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+
+	row, err := tx.SelectOne(context.Background(), "jobs", nil, sqlx.Map{"state": "queued"}, sqlx.WithLock(sqlx.ForUpdate()))
+	if row != nil || !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("SelectOne() = %v, %v, want ErrDriverNotSupported", row, err)
+	}
+}
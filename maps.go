@@ -0,0 +1,171 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ScanMap scans the current row of rows into a Map keyed by column name.
+//
+// Values keep the type the driver returns, except that []byte values of
+// text, integer and floating-point columns, as reported by the column's
+// database type name, become string, int64 (uint64 if out of range) and
+// float64. Binary and unknown columns stay []byte and NULL is nil. If
+// several columns share a name, the last one wins; use rows.Columns for the
+// column order.
+func ScanMap(rows *sql.Rows) (Map, error) {
+	scanner, err := newMapScanner(rows)
+	if err != nil {
+		return nil, err
+	}
+	return scanner.scan(rows)
+}
+
+// ScanMaps scans all remaining rows into Maps, as ScanMap does, and closes
+// rows. The column names are returned in result order, since Maps are
+// unordered and rows can no longer report them. An empty result is an empty
+// slice.
+func ScanMaps(rows *sql.Rows) ([]Map, []string, error) {
+	defer rows.Close()
+
+	scanner, err := newMapScanner(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := []Map{}
+	for rows.Next() {
+		m, err := scanner.scan(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return result, scanner.columns, nil
+}
+
+// QueryMaps runs a query and scans every row into a Map, as ScanMaps does,
+// returning the column names in result order. Unlike Query, the timeout
+// context stays alive until all rows have been read.
+func (db *DB) QueryMaps(ctx context.Context, query string, args ...any) ([]Map, []string, error) {
+	rows, cancel, err := db.queryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cancel()
+
+	return ScanMaps(rows)
+}
+
+// mapScanner scans rows into Maps using the column metadata of a result set.
+type mapScanner struct {
+	columns []string
+	kinds   []columnKind
+	values  []any
+}
+
+// newMapScanner reads the columns and column types of rows.
+func newMapScanner(rows *sql.Rows) (*mapScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("get columns: %w", err)
+	}
+
+	// Without type information []byte values are left as they are.
+	kinds := make([]columnKind, len(columns))
+	if types, err := rows.ColumnTypes(); err == nil && len(types) == len(columns) {
+		for i, columnType := range types {
+			kinds[i] = columnKindOf(columnType.DatabaseTypeName())
+		}
+	}
+
+	values := make([]any, len(columns))
+	for i := range values {
+		values[i] = new(any)
+	}
+
+	return &mapScanner{columns: columns, kinds: kinds, values: values}, nil
+}
+
+// scan scans the current row into a new Map.
+func (s *mapScanner) scan(rows *sql.Rows) (Map, error) {
+	if err := rows.Scan(s.values...); err != nil {
+		return nil, fmt.Errorf("scan row: %w", err)
+	}
+
+	m := make(Map, len(s.columns))
+	for i, column := range s.columns {
+		m[column] = s.kinds[i].value(*(s.values[i].(*any)))
+	}
+	return m, nil
+}
+
+// columnKind classifies a column by its database type name.
+type columnKind int
+
+const (
+	binaryColumn columnKind = iota
+	textColumn
+	intColumn
+	floatColumn
+)
+
+// columnKindOf classifies a database type name such as "VARCHAR",
+// "UNSIGNED BIGINT" or "int4". Unknown names are binaryColumn.
+func columnKindOf(typeName string) columnKind {
+	name := strings.ToUpper(strings.TrimSpace(typeName))
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	name = strings.TrimPrefix(name, "UNSIGNED ")
+	name = strings.TrimSuffix(name, " UNSIGNED")
+
+	switch name {
+	case "CHAR", "VARCHAR", "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT",
+		"NCHAR", "NVARCHAR", "NTEXT", "BPCHAR", "CHARACTER", "CHARACTER VARYING",
+		"CITEXT", "NAME", "ENUM", "SET", "JSON", "JSONB", "XML", "UUID",
+		"UNIQUEIDENTIFIER", "DECIMAL", "NUMERIC", "MONEY",
+		"DATE", "TIME", "DATETIME", "DATETIME2", "DATETIMEOFFSET", "SMALLDATETIME",
+		"TIMESTAMP", "TIMESTAMPTZ", "TIMETZ", "INTERVAL":
+		return textColumn
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT",
+		"INT2", "INT4", "INT8", "YEAR":
+		return intColumn
+	case "FLOAT", "DOUBLE", "DOUBLE PRECISION", "REAL", "FLOAT4", "FLOAT8":
+		return floatColumn
+	}
+	return binaryColumn
+}
+
+// value converts a scanned value of a column of this kind. Only []byte
+// values are converted; text that does not parse as a number stays a string.
+func (k columnKind) value(v any) any {
+	b, ok := v.([]byte)
+	if !ok || k == binaryColumn {
+		return v
+	}
+
+	text := string(b)
+	switch k {
+	case intColumn:
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return n
+		}
+	case floatColumn:
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
@@ -0,0 +1,106 @@
+package sqlx_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestScanMaps(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	reports := fakeResult{
+		columns: []string{"name", "id", "total", "ratio", "payload", "note", "created_at", "raw"},
+		types:   []string{"VARCHAR", "UNSIGNED BIGINT", "DECIMAL", "DOUBLE", "BLOB", "TEXT", "DATETIME", ""},
+		rows: [][]driver.Value{
+			{[]byte("Alice"), []byte("18446744073709551615"), []byte("12.50"), []byte("0.25"), []byte{0xff}, nil, created, []byte("x")},
+			{"Bob", int64(2), []byte("1.00"), 1.5, []byte{}, []byte("hi"), []byte("2024-01-02 03:04:05"), nil},
+		},
+	}
+	ctx := context.Background()
+
+	raw := openFakeDB(t, map[string]fakeResult{"SELECT report": reports})
+	rows, err := raw.QueryContext(ctx, "SELECT report")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	maps, columns, err := sqlx.ScanMaps(rows)
+	if err != nil {
+		t.Fatalf("ScanMaps failed: %v", err)
+	}
+	if len(maps) != 2 {
+		t.Fatalf("ScanMaps returned %d rows, want 2", len(maps))
+	}
+	if !reflect.DeepEqual(columns, reports.columns) {
+		t.Errorf("ScanMaps columns = %v, want %v", columns, reports.columns)
+	}
+
+	first := maps[0]
+	want := sqlx.Map{
+		"name":       "Alice",
+		"id":         uint64(18446744073709551615),
+		"total":      "12.50",
+		"ratio":      0.25,
+		"note":       nil,
+		"created_at": created,
+	}
+	for column, value := range want {
+		if !reflect.DeepEqual(first[column], value) {
+			t.Errorf("%s = %#v, want %#v", column, first[column], value)
+		}
+	}
+	if b, ok := first["payload"].([]byte); !ok || !bytes.Equal(b, []byte{0xff}) {
+		t.Errorf("payload = %#v, want []byte", first["payload"])
+	}
+	if b, ok := first["raw"].([]byte); !ok || string(b) != "x" {
+		t.Errorf("raw = %#v, want []byte for an unknown type", first["raw"])
+	}
+
+	second := maps[1]
+	if second["id"] != int64(2) || second["name"] != "Bob" || second["note"] != "hi" || second["created_at"] != "2024-01-02 03:04:05" || second["raw"] != nil {
+		t.Errorf("second = %#v", second)
+	}
+
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"SELECT report":  reports,
+		"SELECT nothing": {columns: []string{"b", "a"}},
+	})
+	result, columns, err := db.QueryMaps(ctx, "SELECT report")
+	if err != nil || len(result) != 2 || !reflect.DeepEqual(columns, reports.columns) {
+		t.Errorf("QueryMaps() = %d rows, %v, %v", len(result), columns, err)
+	}
+
+	empty, columns, err := db.QueryMaps(ctx, "SELECT nothing")
+	if err != nil || empty == nil || len(empty) != 0 || !reflect.DeepEqual(columns, []string{"b", "a"}) {
+		t.Errorf("QueryMaps() on empty result = %#v, %v, %v", empty, columns, err)
+	}
+}
+
+func TestScanMap(t *testing.T) {
+	db := openFakeDB(t, map[string]fakeResult{
+		"SELECT single": {
+			columns: []string{"count"},
+			types:   []string{"int8"},
+			rows:    [][]driver.Value{{[]byte("42")}},
+		},
+	})
+
+	rows, err := db.QueryContext(context.Background(), "SELECT single")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal("expected a row")
+	}
+	m, err := sqlx.ScanMap(rows)
+	if err != nil || m["count"] != int64(42) {
+		t.Errorf("ScanMap() = %#v, %v", m, err)
+	}
+}
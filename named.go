@@ -0,0 +1,247 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindNamed compiles a query with named parameters into the driver's
+// positional placeholder style and returns the matching arguments.
+//
+// Parameters are written as :name or @name. Names start with a letter or
+// underscore followed by letters, digits or underscores, and may repeat.
+// PostgreSQL casts (::type), MySQL system variables (@@name), and text inside
+// string literals, quoted identifiers and comments are left untouched.
+//
+// Slice values are expanded as by In. Parameters become the driver's
+// placeholders directly, so other '?' characters, such as the PostgreSQL
+// jsonb operators ?, ?| and ?&, are kept as they are; for drivers that use
+// '?' placeholders a bare '?' is an error.
+//
+// arg supplies the values and is a Map, a map with string keys, or a struct
+// (or pointer to one) whose fields are read with the default Binder tag rules.
+func BindNamed(driver Driver, query string, arg any) (string, []any, error) {
+	values, err := namedValues(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	placeholder := func(int) string { return "?" }
+	if dialect := lookupDialect(driver); dialect != nil {
+		placeholder = dialect.Placeholder
+	}
+	positional := placeholder(1) == "?"
+
+	var builder strings.Builder
+	var args []any
+	rules := lexRulesFor(driver)
+
+	for i := 0; i < len(query); {
+		if end := skipNonCode(rules, query, i); end > i {
+			builder.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c == '?' && positional {
+			return "", nil, fmt.Errorf("%w: named query contains a positional '?' placeholder", ErrInvalidArguments)
+		}
+		if c != ':' && c != '@' {
+			builder.WriteByte(c)
+			i++
+			continue
+		}
+
+		// "::type" casts and "@@variables" are not parameters.
+		if i+1 < len(query) && query[i+1] == c {
+			builder.WriteString(query[i : i+2])
+			i += 2
+			continue
+		}
+
+		end := i + 1
+		for end < len(query) && isNameChar(query[end], end == i+1) {
+			end++
+		}
+		if end == i+1 {
+			builder.WriteByte(c)
+			i++
+			continue
+		}
+
+		name := query[i+1 : end]
+		value, ok := values[name]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: no value for named parameter %q", ErrInvalidArguments, name)
+		}
+
+		elems, ok := expandArg(value)
+		if !ok {
+			elems = []any{value}
+		} else if len(elems) == 0 {
+			return "", nil, fmt.Errorf("%w: named parameter %q is an empty slice", ErrInvalidArguments, name)
+		}
+		for j, elem := range elems {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			args = append(args, elem)
+			builder.WriteString(placeholder(len(args)))
+		}
+		i = end
+	}
+
+	return builder.String(), args, nil
+}
+
+// In expands slice arguments bound to a single '?' placeholder into one
+// placeholder per element and flattens them into the argument list, so
+//
+//	In("SELECT * FROM users WHERE id IN (?)", []int{1, 2, 3})
+//
+// yields "SELECT * FROM users WHERE id IN (?, ?, ?)" and three arguments.
+// Byte slices and driver.Valuer implementations are bound as single values.
+// An empty slice is an error, since "IN ()" is not valid SQL.
+//
+// The returned query keeps '?' placeholders; pass it through Rebind (or
+// DB.Rebind) for drivers with another placeholder style.
+func In(query string, args ...any) (string, []any, error) {
+	return expandIn("", query, args)
+}
+
+// expandIn implements In, recognising literals and comments by the
+// driver's quoting rules.
+func expandIn(driver Driver, query string, args []any) (string, []any, error) {
+	if n := countPlaceholders(driver, query); n != len(args) {
+		return "", nil, fmt.Errorf("%w: query has %d placeholders but %d arguments", ErrInvalidArguments, n, len(args))
+	}
+
+	var builder strings.Builder
+	expanded := make([]any, 0, len(args))
+	rules := lexRulesFor(driver)
+	arg := 0
+
+	for i := 0; i < len(query); {
+		if end := skipNonCode(rules, query, i); end > i {
+			builder.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		if query[i] != '?' {
+			builder.WriteByte(query[i])
+			i++
+			continue
+		}
+
+		elems, ok := expandArg(args[arg])
+		if !ok {
+			builder.WriteByte('?')
+			expanded = append(expanded, args[arg])
+		} else if len(elems) == 0 {
+			return "", nil, fmt.Errorf("%w: argument %d is an empty slice", ErrInvalidArguments, arg+1)
+		} else {
+			builder.WriteString(Placeholders(len(elems)))
+			expanded = append(expanded, elems...)
+		}
+		arg++
+		i++
+	}
+
+	return builder.String(), expanded, nil
+}
+
+// NamedExec executes a query with named parameters. See BindNamed.
+func (db *DB) NamedExec(ctx context.Context, query string, arg any) (sql.Result, error) {
+	query, args, err := BindNamed(db.config.Driver, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.Exec(ctx, query, args...)
+}
+
+// NamedQuery executes a query with named parameters that returns rows.
+// See BindNamed.
+func (db *DB) NamedQuery(ctx context.Context, query string, arg any) (*sql.Rows, error) {
+	query, args, err := BindNamed(db.config.Driver, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.Query(ctx, query, args...)
+}
+
+// namedValues returns the named parameter values held by arg.
+func namedValues(arg any) (Map, error) {
+	switch v := arg.(type) {
+	case Map:
+		return v, nil
+	case map[string]any:
+		return v, nil
+	}
+
+	val := reflect.ValueOf(arg)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("%w: named argument is a nil pointer", ErrInvalidArguments)
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		return NewBinder().structToMap(val)
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := make(Map, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("%w: named argument must be a map or struct, got %T", ErrInvalidArguments, arg)
+}
+
+// expandArg returns the elements of a slice argument and whether the
+// argument should be expanded at all.
+func expandArg(arg any) ([]any, bool) {
+	if arg == nil {
+		return nil, false
+	}
+
+	switch arg.(type) {
+	case []byte, driver.Valuer:
+		return nil, false
+	}
+
+	val := reflect.ValueOf(arg)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	elems := make([]any, val.Len())
+	for i := range elems {
+		elems[i] = val.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// isNameChar reports whether c may appear in a parameter name.
+// Digits are not allowed as the first character.
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
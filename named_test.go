@@ -0,0 +1,146 @@
+package sqlx_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestBindNamed(t *testing.T) {
+	type filter struct {
+		MinAge int    `db:"min_age"`
+		Name   string `db:"name"`
+	}
+
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		query    string
+		arg      any
+		expected string
+		args     []any
+	}{
+		{
+			name:     "map on mysql",
+			driver:   sqlx.MySQL,
+			query:    "SELECT * FROM users WHERE age >= :min_age AND name = :name",
+			arg:      sqlx.Map{"min_age": 18, "name": "John"},
+			expected: "SELECT * FROM users WHERE age >= ? AND name = ?",
+			args:     []any{18, "John"},
+		},
+		{
+			name:     "struct on postgres with repeated name",
+			driver:   sqlx.PostgreSQL,
+			query:    "SELECT * FROM users WHERE name = :name OR nickname = :name AND age >= @min_age",
+			arg:      &filter{MinAge: 21, Name: "Jane"},
+			expected: "SELECT * FROM users WHERE name = $1 OR nickname = $2 AND age >= $3",
+			args:     []any{"Jane", "Jane", 21},
+		},
+		{
+			name:     "casts literals and comments ignored",
+			driver:   sqlx.PostgreSQL,
+			query:    "SELECT created_at::date, ':skip' FROM t -- :comment\nWHERE id = :id /* @x */",
+			arg:      map[string]any{"id": 7},
+			expected: "SELECT created_at::date, ':skip' FROM t -- :comment\nWHERE id = $1 /* @x */",
+			args:     []any{7},
+		},
+		{
+			name:     "postgres jsonb operators kept",
+			driver:   sqlx.PostgreSQL,
+			query:    "SELECT * FROM docs WHERE data ? 'k' AND data ?| array[:keys] AND tags ?& array['a'] AND id = :id",
+			arg:      sqlx.Map{"id": 1, "keys": []string{"x", "y"}},
+			expected: "SELECT * FROM docs WHERE data ? 'k' AND data ?| array[$1, $2] AND tags ?& array['a'] AND id = $3",
+			args:     []any{"x", "y", 1},
+		},
+		{
+			name:     "mysql system variables ignored",
+			driver:   sqlx.MySQL,
+			query:    "SELECT @@version, :id",
+			arg:      map[string]int{"id": 1},
+			expected: "SELECT @@version, ?",
+			args:     []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := sqlx.BindNamed(tt.driver, tt.query, tt.arg)
+			if err != nil {
+				t.Fatalf("BindNamed() error = %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("BindNamed() = %q, want %q", query, tt.expected)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("BindNamed() args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBindNamedErrors(t *testing.T) {
+	_, _, err := sqlx.BindNamed(sqlx.MySQL, "SELECT * FROM users WHERE id = :id", sqlx.Map{})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for missing parameter, got %v", err)
+	}
+
+	_, _, err = sqlx.BindNamed(sqlx.MySQL, "SELECT :id", 42)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for scalar argument, got %v", err)
+	}
+
+	_, _, err = sqlx.BindNamed(sqlx.MySQL, "SELECT * FROM users WHERE id = :id AND age = ?", sqlx.Map{"id": 1})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for a positional placeholder, got %v", err)
+	}
+
+	_, _, err = sqlx.BindNamed(sqlx.PostgreSQL, "SELECT * FROM users WHERE id IN (:ids)", sqlx.Map{"ids": []int{}})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for an empty slice, got %v", err)
+	}
+}
+
+func TestIn(t *testing.T) {
+	query, args, err := sqlx.In("SELECT * FROM users WHERE id IN (?) AND state = ? AND tag IN (?)",
+		[]int{1, 2, 3}, "open", []string{"a"})
+	if err != nil {
+		t.Fatalf("In() error = %v", err)
+	}
+	expected := "SELECT * FROM users WHERE id IN (?, ?, ?) AND state = ? AND tag IN (?)"
+	if query != expected {
+		t.Errorf("In() = %q, want %q", query, expected)
+	}
+	if want := []any{1, 2, 3, "open", "a"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("In() args = %v, want %v", args, want)
+	}
+
+	query, args, err = sqlx.In("SELECT '?' FROM files WHERE data = ?", []byte("raw"))
+	if err != nil {
+		t.Fatalf("In() error = %v", err)
+	}
+	if query != "SELECT '?' FROM files WHERE data = ?" || len(args) != 1 {
+		t.Errorf("In() should bind byte slices as one value, got %q with %d args", query, len(args))
+	}
+
+	_, _, err = sqlx.In("SELECT * FROM users WHERE id IN (?)", []int{})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for empty slice, got %v", err)
+	}
+
+	_, _, err = sqlx.In("SELECT * FROM users WHERE id = ? AND age = ?", 1)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for argument mismatch, got %v", err)
+	}
+
+	query, args, err = sqlx.BindNamed(sqlx.PostgreSQL, "SELECT * FROM users WHERE id IN (:ids) AND age > :age",
+		sqlx.Map{"ids": []int64{4, 5}, "age": 30})
+	if err != nil {
+		t.Fatalf("BindNamed() error = %v", err)
+	}
+	expected = "SELECT * FROM users WHERE id IN ($1, $2) AND age > $3"
+	if query != expected || len(args) != 3 {
+		t.Errorf("BindNamed() = %q with %d args, want %q with 3 args", query, len(args), expected)
+	}
+}
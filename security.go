@@ -153,24 +153,85 @@ func (e *SQLiteIdentifierEscaper) QuoteChar() string {
 	return `"`
 }
 
+// SQLServerIdentifierEscaper implements IdentifierEscaper for SQL Server.
+type SQLServerIdentifierEscaper struct{}
+
+// Escape escapes a SQL Server identifier using square brackets.
+func (e *SQLServerIdentifierEscaper) Escape(identifier string) (string, error) {
+	if err := e.Validate(identifier); err != nil {
+		return "", err
+	}
+
+	// Escape closing brackets by doubling them
+	escaped := strings.ReplaceAll(identifier, "]", "]]")
+	return "[" + escaped + "]", nil
+}
+
+// Validate validates a SQL Server identifier.
+func (e *SQLServerIdentifierEscaper) Validate(identifier string) error {
+	if identifier == "" {
+		return fmt.Errorf("%w: identifier cannot be empty", ErrInvalidIdentifier)
+	}
+
+	// SQL Server max identifier length is 128 characters (sysname)
+	if utf8.RuneCountInString(identifier) > 128 {
+		return fmt.Errorf("%w: identifier exceeds maximum length of 128 characters", ErrInvalidIdentifier)
+	}
+
+	// SQL Server regular identifiers may also contain @, $ and #, but those
+	// carry special meaning (variables, temp tables), so we stay restrictive
+	validPattern := regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	if !validPattern.MatchString(identifier) {
+		return fmt.Errorf("%w: identifier %q contains invalid characters for unquoted identifier", ErrInvalidIdentifier, identifier)
+	}
+
+	// Check for T-SQL keywords
+	if isSQLServerReservedWord(identifier) {
+		return fmt.Errorf("%w: identifier %q is a SQL Server reserved word", ErrInvalidIdentifier, identifier)
+	}
+
+	return nil
+}
+
+// QuoteChar returns the opening quote character used by SQL Server.
+func (e *SQLServerIdentifierEscaper) QuoteChar() string {
+	return "["
+}
+
 // DefaultIdentifierEscaper returns the default identifier escaper for MySQL.
 func DefaultIdentifierEscaper() IdentifierEscaper {
 	return &MySQLIdentifierEscaper{}
 }
 
-// GetIdentifierEscaper returns an appropriate identifier escaper for the given driver.
+// GetIdentifierEscaper returns the identifier escaper of the driver's dialect.
+// For a driver without a registered dialect it returns an escaper that
+// rejects every identifier with ErrDriverNotSupported, since its quoting
+// rules are unknown.
 func GetIdentifierEscaper(driver Driver) IdentifierEscaper {
-	switch driver {
-	case MySQL:
-		return &MySQLIdentifierEscaper{}
-	case PostgreSQL:
-		return &PostgreSQLIdentifierEscaper{}
-	case SQLite:
-		return &SQLiteIdentifierEscaper{}
-	default:
-		// Default to MySQL escaper for unknown drivers
-		return &MySQLIdentifierEscaper{}
+	if dialect := lookupDialect(driver); dialect != nil {
+		return dialect.Escaper()
 	}
+	return unsupportedEscaper{driver: driver}
+}
+
+// unsupportedEscaper is the escaper of a driver without a registered dialect.
+type unsupportedEscaper struct {
+	driver Driver
+}
+
+// Escape returns ErrDriverNotSupported.
+func (e unsupportedEscaper) Escape(identifier string) (string, error) {
+	return "", e.Validate(identifier)
+}
+
+// Validate returns ErrDriverNotSupported.
+func (e unsupportedEscaper) Validate(identifier string) error {
+	return fmt.Errorf("%w: cannot escape identifier %q, no dialect registered for %q", ErrDriverNotSupported, identifier, e.driver)
+}
+
+// QuoteChar returns an empty string.
+func (e unsupportedEscaper) QuoteChar() string {
+	return ""
 }
 
 // isMySQLReservedWord checks if a word is a MySQL reserved word.
@@ -260,6 +321,64 @@ func isPostgreSQLReservedWord(word string) bool {
 	return reservedWords[word]
 }
 
+// isSQLServerReservedWord checks if a word is a SQL Server reserved word.
+func isSQLServerReservedWord(word string) bool {
+	word = strings.ToUpper(word)
+
+	// Transact-SQL reserved keywords
+	reservedWords := map[string]bool{
+		"ADD": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true,
+		"AS": true, "ASC": true, "AUTHORIZATION": true, "BACKUP": true,
+		"BEGIN": true, "BETWEEN": true, "BREAK": true, "BROWSE": true,
+		"BULK": true, "BY": true, "CASCADE": true, "CASE": true,
+		"CHECK": true, "CHECKPOINT": true, "CLOSE": true, "CLUSTERED": true,
+		"COALESCE": true, "COLLATE": true, "COLUMN": true, "COMMIT": true,
+		"COMPUTE": true, "CONSTRAINT": true, "CONTAINS": true,
+		"CONTAINSTABLE": true, "CONTINUE": true, "CONVERT": true,
+		"CREATE": true, "CROSS": true, "CURRENT": true, "CURRENT_DATE": true,
+		"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true,
+		"CURSOR": true, "DATABASE": true, "DBCC": true, "DEALLOCATE": true,
+		"DECLARE": true, "DEFAULT": true, "DELETE": true, "DENY": true,
+		"DESC": true, "DISK": true, "DISTINCT": true, "DISTRIBUTED": true,
+		"DOUBLE": true, "DROP": true, "DUMP": true, "ELSE": true, "END": true,
+		"ERRLVL": true, "ESCAPE": true, "EXCEPT": true, "EXEC": true,
+		"EXECUTE": true, "EXISTS": true, "EXIT": true, "EXTERNAL": true,
+		"FETCH": true, "FILE": true, "FILLFACTOR": true, "FOR": true,
+		"FOREIGN": true, "FREETEXT": true, "FREETEXTTABLE": true, "FROM": true,
+		"FULL": true, "FUNCTION": true, "GOTO": true, "GRANT": true,
+		"GROUP": true, "HAVING": true, "HOLDLOCK": true, "IDENTITY": true,
+		"IDENTITY_INSERT": true, "IDENTITYCOL": true, "IF": true, "IN": true,
+		"INDEX": true, "INNER": true, "INSERT": true, "INTERSECT": true,
+		"INTO": true, "IS": true, "JOIN": true, "KEY": true, "KILL": true,
+		"LEFT": true, "LIKE": true, "LINENO": true, "LOAD": true,
+		"MERGE": true, "NATIONAL": true, "NOCHECK": true, "NONCLUSTERED": true,
+		"NOT": true, "NULL": true, "NULLIF": true, "OF": true, "OFF": true,
+		"OFFSETS": true, "ON": true, "OPEN": true, "OPENDATASOURCE": true,
+		"OPENQUERY": true, "OPENROWSET": true, "OPENXML": true, "OPTION": true,
+		"OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+		"PERCENT": true, "PIVOT": true, "PLAN": true, "PRECISION": true,
+		"PRIMARY": true, "PRINT": true, "PROC": true, "PROCEDURE": true,
+		"PUBLIC": true, "RAISERROR": true, "READ": true, "READTEXT": true,
+		"RECONFIGURE": true, "REFERENCES": true, "REPLICATION": true,
+		"RESTORE": true, "RESTRICT": true, "RETURN": true, "REVERT": true,
+		"REVOKE": true, "RIGHT": true, "ROLLBACK": true, "ROWCOUNT": true,
+		"ROWGUIDCOL": true, "RULE": true, "SAVE": true, "SCHEMA": true,
+		"SECURITYAUDIT": true, "SELECT": true, "SEMANTICKEYPHRASETABLE": true,
+		"SEMANTICSIMILARITYDETAILSTABLE": true, "SEMANTICSIMILARITYTABLE": true,
+		"SESSION_USER": true, "SET": true, "SETUSER": true, "SHUTDOWN": true,
+		"SOME": true, "STATISTICS": true, "SYSTEM_USER": true, "TABLE": true,
+		"TABLESAMPLE": true, "TEXTSIZE": true, "THEN": true, "TO": true,
+		"TOP": true, "TRAN": true, "TRANSACTION": true, "TRIGGER": true,
+		"TRUNCATE": true, "TRY_CONVERT": true, "TSEQUAL": true, "UNION": true,
+		"UNIQUE": true, "UNPIVOT": true, "UPDATE": true, "UPDATETEXT": true,
+		"USE": true, "USER": true, "VALUES": true, "VARYING": true,
+		"VIEW": true, "WAITFOR": true, "WHEN": true, "WHERE": true,
+		"WHILE": true, "WITH": true, "WITHIN": true, "WRITETEXT": true,
+	}
+
+	return reservedWords[word]
+}
+
 // isSQLiteReservedWord checks if a word is a SQLite reserved word.
 func isSQLiteReservedWord(word string) bool {
 	word = strings.ToUpper(word)
@@ -304,6 +423,8 @@ func isSQLiteReservedWord(word string) bool {
 }
 
 // SafeIdentifier represents a validated and escaped SQL identifier.
+// Identifiers may be qualified with dots, as in "analytics.events" or "u.id";
+// each part is validated and escaped separately.
 type SafeIdentifier struct {
 	// original is the original identifier string
 	original string
@@ -311,6 +432,12 @@ type SafeIdentifier struct {
 	// escaped is the escaped identifier string
 	escaped string
 
+	// parts are the dot-separated parts of the original identifier
+	parts []string
+
+	// allowStar permits "*" as the last part
+	allowStar bool
+
 	// escaper is the identifier escaper used
 	escaper IdentifierEscaper
 
@@ -318,22 +445,76 @@ type SafeIdentifier struct {
 	driver Driver
 }
 
+// maxIdentifierParts is the maximum number of parts in a qualified
+// identifier, enough for database.schema.table.column.
+const maxIdentifierParts = 4
+
 // NewSafeIdentifier creates a new SafeIdentifier for the given driver.
+// Qualified names such as "analytics.events" are accepted; "*" is not.
 func NewSafeIdentifier(driver Driver, identifier string) (*SafeIdentifier, error) {
+	return newSafeIdentifier(driver, identifier, false)
+}
+
+// NewSafeIdentifierWithStar is like NewSafeIdentifier but also accepts "*"
+// as the last part, as in "*" or "t.*", for use in select lists.
+func NewSafeIdentifierWithStar(driver Driver, identifier string) (*SafeIdentifier, error) {
+	return newSafeIdentifier(driver, identifier, true)
+}
+
+// newSafeIdentifier validates and escapes each part of a qualified identifier.
+func newSafeIdentifier(driver Driver, identifier string, allowStar bool) (*SafeIdentifier, error) {
 	escaper := GetIdentifierEscaper(driver)
-	escaped, err := escaper.Escape(identifier)
-	if err != nil {
+	parts := strings.Split(identifier, ".")
+
+	if err := validateQualified(escaper, parts, allowStar); err != nil {
 		return nil, err
 	}
 
+	escapedParts := make([]string, len(parts))
+	for i, part := range parts {
+		if part == "*" {
+			escapedParts[i] = part
+			continue
+		}
+
+		escaped, err := escaper.Escape(part)
+		if err != nil {
+			return nil, err
+		}
+		escapedParts[i] = escaped
+	}
+
 	return &SafeIdentifier{
-		original: identifier,
-		escaped:  escaped,
-		escaper:  escaper,
-		driver:   driver,
+		original:  identifier,
+		escaped:   strings.Join(escapedParts, "."),
+		parts:     parts,
+		allowStar: allowStar,
+		escaper:   escaper,
+		driver:    driver,
 	}, nil
 }
 
+// validateQualified validates the parts of a qualified identifier.
+func validateQualified(escaper IdentifierEscaper, parts []string, allowStar bool) error {
+	if len(parts) > maxIdentifierParts {
+		return fmt.Errorf("%w: identifier has more than %d parts", ErrInvalidIdentifier, maxIdentifierParts)
+	}
+
+	for i, part := range parts {
+		if part == "*" {
+			if !allowStar || i != len(parts)-1 {
+				return fmt.Errorf("%w: '*' is only allowed as the last part of a select column", ErrInvalidIdentifier)
+			}
+			continue
+		}
+
+		if err := escaper.Validate(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // MustSafeIdentifier creates a new SafeIdentifier, panicking on error.
 // Use only when you are certain the identifier is valid.
 func MustSafeIdentifier(driver Driver, identifier string) *SafeIdentifier {
@@ -361,7 +542,17 @@ func (si *SafeIdentifier) Driver() Driver {
 
 // Validate validates the identifier.
 func (si *SafeIdentifier) Validate() error {
-	return si.escaper.Validate(si.original)
+	return validateQualified(si.escaper, si.parts, si.allowStar)
+}
+
+// Parts returns the original dot-separated parts of the identifier.
+func (si *SafeIdentifier) Parts() []string {
+	return append([]string(nil), si.parts...)
+}
+
+// IsQualified returns true if the identifier has more than one part.
+func (si *SafeIdentifier) IsQualified() bool {
+	return len(si.parts) > 1
 }
 
 // QuoteChar returns the quote character used.
@@ -371,7 +562,7 @@ func (si *SafeIdentifier) QuoteChar() string {
 
 // IsValid returns true if the identifier is valid.
 func (si *SafeIdentifier) IsValid() bool {
-	return si.escaper.Validate(si.original) == nil
+	return si.Validate() == nil
 }
 
 // EscapeTableName creates a safe table identifier.
@@ -392,6 +583,16 @@ func EscapeColumnName(driver Driver, columnName string) (string, error) {
 	return si.String(), nil
 }
 
+// EscapeColumnNameWithStar creates a safe select-list column identifier,
+// accepting "*" and "t.*" in addition to column names.
+func EscapeColumnNameWithStar(driver Driver, columnName string) (string, error) {
+	si, err := NewSafeIdentifierWithStar(driver, columnName)
+	if err != nil {
+		return "", err
+	}
+	return si.String(), nil
+}
+
 // EscapeIdentifier creates a safe SQL identifier.
 func EscapeIdentifier(driver Driver, identifier string) (string, error) {
 	si, err := NewSafeIdentifier(driver, identifier)
@@ -416,42 +617,54 @@ func MustEscapeIdentifier(driver Driver, identifier string) string {
 	return MustSafeIdentifier(driver, identifier).String()
 }
 
-// ValidateTableName validates a table name.
+// ValidateTableName validates a possibly schema-qualified table name.
 func ValidateTableName(driver Driver, tableName string) error {
 	escaper := GetIdentifierEscaper(driver)
-	return escaper.Validate(tableName)
+	return validateQualified(escaper, strings.Split(tableName, "."), false)
 }
 
-// ValidateColumnName validates a column name.
+// ValidateColumnName validates a possibly table-qualified column name.
 func ValidateColumnName(driver Driver, columnName string) error {
 	escaper := GetIdentifierEscaper(driver)
-	return escaper.Validate(columnName)
+	return validateQualified(escaper, strings.Split(columnName, "."), false)
 }
 
-// ValidateIdentifier validates a SQL identifier.
+// ValidateIdentifier validates a possibly qualified SQL identifier.
 func ValidateIdentifier(driver Driver, identifier string) error {
 	escaper := GetIdentifierEscaper(driver)
-	return escaper.Validate(identifier)
+	return validateQualified(escaper, strings.Split(identifier, "."), false)
 }
 
 // SafeIdentifierList represents a list of safe identifiers.
 type SafeIdentifierList struct {
 	identifiers []*SafeIdentifier
 	driver      Driver
+	allowStar   bool
 }
 
 // NewSafeIdentifierList creates a new SafeIdentifierList.
+// Identifiers may be qualified; "*" is not accepted.
 func NewSafeIdentifierList(driver Driver, identifiers ...string) (*SafeIdentifierList, error) {
+	return newSafeIdentifierList(driver, false, identifiers)
+}
+
+// NewSafeIdentifierListWithStar creates a SafeIdentifierList for a select
+// list, which also accepts "*" and "t.*".
+func NewSafeIdentifierListWithStar(driver Driver, identifiers ...string) (*SafeIdentifierList, error) {
+	return newSafeIdentifierList(driver, true, identifiers)
+}
+
+// newSafeIdentifierList creates a list and adds each identifier to it.
+func newSafeIdentifierList(driver Driver, allowStar bool, identifiers []string) (*SafeIdentifierList, error) {
 	list := &SafeIdentifierList{
-		driver: driver,
+		driver:    driver,
+		allowStar: allowStar,
 	}
 
 	for _, id := range identifiers {
-		si, err := NewSafeIdentifier(driver, id)
-		if err != nil {
+		if err := list.Add(id); err != nil {
 			return nil, err
 		}
-		list.identifiers = append(list.identifiers, si)
 	}
 
 	return list, nil
@@ -459,7 +672,7 @@ func NewSafeIdentifierList(driver Driver, identifiers ...string) (*SafeIdentifie
 
 // Add adds an identifier to the list.
 func (sil *SafeIdentifierList) Add(identifier string) error {
-	si, err := NewSafeIdentifier(sil.driver, identifier)
+	si, err := newSafeIdentifier(sil.driver, identifier, sil.allowStar)
 	if err != nil {
 		return err
 	}
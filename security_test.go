@@ -1,6 +1,7 @@
 package sqlx_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/dongrv/sqlx"
@@ -239,7 +240,7 @@ func TestGetIdentifierEscaper(t *testing.T) {
 		{"MySQL", sqlx.MySQL, "`"},
 		{"PostgreSQL", sqlx.PostgreSQL, "\""},
 		{"SQLite", sqlx.SQLite, "\""},
-		{"Unknown", sqlx.Driver("unknown"), "`"}, // Defaults to MySQL
+		{"SQLServer", sqlx.SQLServer, "["},
 	}
 
 	for _, tt := range tests {
@@ -250,6 +251,14 @@ func TestGetIdentifierEscaper(t *testing.T) {
 			}
 		})
 	}
+
+	unknown := sqlx.Driver("unknown")
+	if _, err := sqlx.GetIdentifierEscaper(unknown).Escape("users"); !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("Escape() for an unknown driver error = %v, want ErrDriverNotSupported", err)
+	}
+	if _, err := sqlx.EscapeTableName(unknown, "users"); !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("EscapeTableName() for an unknown driver error = %v, want ErrDriverNotSupported", err)
+	}
 }
 
 func TestSafeIdentifier(t *testing.T) {
@@ -455,3 +464,101 @@ func TestSafeIdentifierList(t *testing.T) {
 		t.Errorf("After Clear, Length() = %d, want 0", list.Length())
 	}
 }
+
+func TestQualifiedIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		input    string
+		star     bool
+		expected string
+		wantErr  bool
+	}{
+		{"mysql column", sqlx.MySQL, "u.id", false, "`u`.`id`", false},
+		{"mysql database table", sqlx.MySQL, "shop.orders", false, "`shop`.`orders`", false},
+		{"postgres schema table", sqlx.PostgreSQL, "analytics.events", false, `"analytics"."events"`, false},
+		{"postgres three parts", sqlx.PostgreSQL, "analytics.events.id", false, `"analytics"."events"."id"`, false},
+		{"sqlserver schema table", sqlx.SQLServer, "dbo.users", false, "[dbo].[users]", false},
+		{"star not allowed", sqlx.MySQL, "u.*", false, "", true},
+		{"qualified star", sqlx.MySQL, "u.*", true, "`u`.*", false},
+		{"bare star", sqlx.PostgreSQL, "*", true, "*", false},
+		{"star not last", sqlx.MySQL, "*.id", true, "", true},
+		{"empty part", sqlx.MySQL, "a..b", false, "", true},
+		{"leading dot", sqlx.MySQL, ".id", false, "", true},
+		{"trailing dot", sqlx.MySQL, "u.", false, "", true},
+		{"invalid part", sqlx.MySQL, "u.1id", false, "", true},
+		{"reserved part", sqlx.MySQL, "u.select", false, "", true},
+		{"too many parts", sqlx.PostgreSQL, "a.b.c.d.e", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				si  *sqlx.SafeIdentifier
+				err error
+			)
+			if tt.star {
+				si, err = sqlx.NewSafeIdentifierWithStar(tt.driver, tt.input)
+			} else {
+				si, err = sqlx.NewSafeIdentifier(tt.driver, tt.input)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if si.String() != tt.expected {
+				t.Errorf("String() = %q, want %q", si.String(), tt.expected)
+			}
+			if !si.IsValid() {
+				t.Error("IsValid() = false, want true")
+			}
+		})
+	}
+
+	si := sqlx.MustSafeIdentifier(sqlx.PostgreSQL, "analytics.events")
+	if !si.IsQualified() {
+		t.Error("IsQualified() = false, want true")
+	}
+	if parts := si.Parts(); len(parts) != 2 || parts[0] != "analytics" || parts[1] != "events" {
+		t.Errorf("Parts() = %v, want [analytics events]", parts)
+	}
+
+	escaped, err := sqlx.EscapeTableName(sqlx.PostgreSQL, "analytics.events")
+	if err != nil || escaped != `"analytics"."events"` {
+		t.Errorf("EscapeTableName() = %q, %v", escaped, err)
+	}
+	escaped, err = sqlx.EscapeColumnNameWithStar(sqlx.MySQL, "u.*")
+	if err != nil || escaped != "`u`.*" {
+		t.Errorf("EscapeColumnNameWithStar() = %q, %v", escaped, err)
+	}
+	if _, err := sqlx.EscapeColumnName(sqlx.MySQL, "*"); err == nil {
+		t.Error("EscapeColumnName(\"*\") should have failed")
+	}
+	if err := sqlx.ValidateColumnName(sqlx.MySQL, "u.id"); err != nil {
+		t.Errorf("ValidateColumnName(u.id) failed: %v", err)
+	}
+	if err := sqlx.ValidateTableName(sqlx.MySQL, "a..b"); err == nil {
+		t.Error("ValidateTableName(a..b) should have failed")
+	}
+
+	list, err := sqlx.NewSafeIdentifierList(sqlx.MySQL, "u.id", "o.total")
+	if err != nil {
+		t.Fatalf("NewSafeIdentifierList failed: %v", err)
+	}
+	if joined := list.Join(", "); joined != "`u`.`id`, `o`.`total`" {
+		t.Errorf("Join() = %q", joined)
+	}
+	if err := list.Add("u.*"); err == nil {
+		t.Error("Add(u.*) should have failed without star allowance")
+	}
+
+	list, err = sqlx.NewSafeIdentifierListWithStar(sqlx.MySQL, "u.*", "o.total")
+	if err != nil {
+		t.Fatalf("NewSafeIdentifierListWithStar failed: %v", err)
+	}
+	if joined := list.Join(", "); joined != "`u`.*, `o`.`total`" {
+		t.Errorf("Join() = %q", joined)
+	}
+}
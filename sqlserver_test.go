@@ -0,0 +1,156 @@
+package sqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dongrv/sqlx"
+)
+
+func TestSQLServerIdentifierEscaper(t *testing.T) {
+	escaper := &sqlx.SQLServerIdentifierEscaper{}
+
+	escaped, err := escaper.Escape("order_items")
+	if err != nil || escaped != "[order_items]" {
+		t.Errorf("Escape() = %q, %v, want %q", escaped, err, "[order_items]")
+	}
+
+	for _, identifier := range []string{"", "MERGE", "top", "name]; DROP TABLE x; --"} {
+		if err := escaper.Validate(identifier); !errors.Is(err, sqlx.ErrInvalidIdentifier) {
+			t.Errorf("Validate(%q) error = %v, want %v", identifier, err, sqlx.ErrInvalidIdentifier)
+		}
+	}
+}
+
+func TestSQLServerQueries(t *testing.T) {
+	query, args, err := sqlx.BuildSelectQueryWithDriver(sqlx.SQLServer, "users", []string{"id", "name"},
+		sqlx.And(sqlx.Col("age").Gte(18), sqlx.Col("kind").In("a", "b")))
+	if err != nil {
+		t.Fatalf("BuildSelectQueryWithDriver failed: %v", err)
+	}
+	expected := "SELECT [id], [name] FROM [users] WHERE [age] >= @p1 AND [kind] IN (@p2, @p3)"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+	if len(args) != 3 {
+		t.Errorf("Expected 3 args, got %d", len(args))
+	}
+
+	query, _, err = sqlx.NewSelectBuilder(sqlx.SQLServer).From("users").OrderBy("id").Limit(10).Offset(20).ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	expected = "SELECT * FROM [users] ORDER BY [id] ASC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+
+	_, _, err = sqlx.NewSelectBuilder(sqlx.SQLServer).From("users").Limit(10).ToSQL()
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for LIMIT without ORDER BY, got %v", err)
+	}
+}
+
+func TestSQLServerPaginate(t *testing.T) {
+	query, err := sqlx.PaginateWithDriver(sqlx.SQLServer, "SELECT * FROM [order] ORDER BY id", 2, 25)
+	if err != nil {
+		t.Fatalf("PaginateWithDriver failed: %v", err)
+	}
+	expected := "SELECT * FROM [order] ORDER BY id OFFSET 25 ROWS FETCH NEXT 25 ROWS ONLY"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+
+	_, err = sqlx.PaginateWithDriver(sqlx.SQLServer, "SELECT * FROM [users ORDER BY x]", 1, 10)
+	if !errors.Is(err, sqlx.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery without ORDER BY, got %v", err)
+	}
+}
+
+func TestSQLServerUpsert(t *testing.T) {
+	data := map[string]any{"email": "john@example.com", "name": "John"}
+
+	query, args, err := sqlx.BuildUpsertQueryWithDriver(sqlx.SQLServer, "users", data, []string{"email"}, nil)
+	if err != nil {
+		t.Fatalf("BuildUpsertQueryWithDriver failed: %v", err)
+	}
+	expected := "MERGE INTO [users] WITH (HOLDLOCK) AS target USING (VALUES (@p1, @p2)) AS source ([email], [name])" +
+		" ON target.[email] = source.[email]" +
+		" WHEN MATCHED THEN UPDATE SET target.[name] = source.[name]" +
+		" WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES (source.[email], source.[name]);"
+	if query != expected {
+		t.Errorf("BuildUpsertQueryWithDriver() = %q, want %q", query, expected)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(args))
+	}
+
+	query, _, err = sqlx.BuildInsertIgnoreQueryWithDriver(sqlx.SQLServer, "users", data, []string{"email"})
+	if err != nil {
+		t.Fatalf("BuildInsertIgnoreQueryWithDriver failed: %v", err)
+	}
+	expected = "MERGE INTO [users] WITH (HOLDLOCK) AS target USING (VALUES (@p1, @p2)) AS source ([email], [name])" +
+		" ON target.[email] = source.[email]" +
+		" WHEN NOT MATCHED THEN INSERT ([email], [name]) VALUES (source.[email], source.[name]);"
+	if query != expected {
+		t.Errorf("BuildInsertIgnoreQueryWithDriver() = %q, want %q", query, expected)
+	}
+
+	_, _, err = sqlx.BuildInsertIgnoreQueryWithDriver(sqlx.SQLServer, "users", data, nil)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments without conflict columns, got %v", err)
+	}
+}
+
+func TestSQLServerOutput(t *testing.T) {
+	insert, _, err := sqlx.BuildInsertQueryWithDriver(sqlx.SQLServer, "users", map[string]any{"name": "John"})
+	if err != nil {
+		t.Fatalf("BuildInsertQueryWithDriver failed: %v", err)
+	}
+	update, _, err := sqlx.BuildUpdateQueryWithDriver(sqlx.SQLServer, "users", map[string]any{"name": "Jane"}, sqlx.Col("id").Eq(1))
+	if err != nil {
+		t.Fatalf("BuildUpdateQueryWithDriver failed: %v", err)
+	}
+	del, _, err := sqlx.BuildDeleteQueryWithDriver(sqlx.SQLServer, "users", sqlx.Col("id").Eq(1))
+	if err != nil {
+		t.Fatalf("BuildDeleteQueryWithDriver failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		columns  []string
+		expected string
+	}{
+		{"insert all", insert, nil, "INSERT INTO [users] ([name]) OUTPUT INSERTED.* VALUES (@p1)"},
+		{"update columns", update, []string{"id", "name"}, "UPDATE [users] SET [name] = @p1 OUTPUT INSERTED.[id], INSERTED.[name] WHERE [id] = @p2"},
+		{"delete", del, []string{"id"}, "DELETE FROM [users] OUTPUT DELETED.[id] WHERE [id] = @p1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := sqlx.ReturningWithDriver(sqlx.SQLServer, tt.query, tt.columns...)
+			if err != nil {
+				t.Fatalf("ReturningWithDriver() error = %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("ReturningWithDriver() = %q, want %q", query, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLServerDialectHelpers(t *testing.T) {
+	nullSafe, err := sqlx.NullSafeEqualWithDriver(sqlx.SQLServer, "deleted_at")
+	if err != nil || nullSafe != "EXISTS (SELECT [deleted_at] INTERSECT SELECT ?)" {
+		t.Errorf("NullSafeEqualWithDriver() = %q, %v", nullSafe, err)
+	}
+
+	query, args, err := sqlx.BindNamed(sqlx.SQLServer, "SELECT [a:b] FROM t WHERE id = :id AND x = @@ROWCOUNT", sqlx.Map{"id": 1})
+	if err != nil {
+		t.Fatalf("BindNamed() error = %v", err)
+	}
+	if query != "SELECT [a:b] FROM t WHERE id = @p1 AND x = @@ROWCOUNT" || len(args) != 1 {
+		t.Errorf("BindNamed() = %q with %d args", query, len(args))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -116,8 +118,19 @@ const (
 	PostgreSQL Driver = "postgres"
 	// SQLite driver.
 	SQLite Driver = "sqlite3"
+	// SQLServer driver.
+	SQLServer Driver = "sqlserver"
 )
 
+// defaultMaxPlaceholders returns the bind parameter limit of a driver's
+// dialect, or a conservative 999 for unregistered drivers.
+func defaultMaxPlaceholders(driver Driver) int {
+	if dialect := lookupDialect(driver); dialect != nil {
+		return dialect.MaxPlaceholders()
+	}
+	return 999
+}
+
 // Config holds database connection configuration.
 type Config struct {
 	// Driver is the database driver (e.g., "mysql", "postgres").
@@ -160,6 +173,29 @@ type Config struct {
 
 	// RetryDelay is the delay between retries.
 	RetryDelay time.Duration
+
+	// MaxPlaceholders caps the number of bind parameters in a single statement,
+	// which determines how multi-row inserts are chunked.
+	// Zero means the driver default (65535 for MySQL and PostgreSQL, 32766 for
+	// SQLite, 2098 for SQL Server).
+	// Set it to 999 for SQLite builds older than 3.32.
+	MaxPlaceholders int
+
+	// PrimaryKey is the key column used to re-read inserted rows on drivers
+	// without RETURNING support (MySQL). Empty means "id".
+	PrimaryKey string
+
+	// Time controls how times are bound to structs and written in queries.
+	Time TimeSettings
+
+	// Logger, if set, is passed every query run through DB and Tx with its
+	// arguments, duration and error. See PrintfLogger.
+	Logger QueryLogger
+
+	// QueryInErrors wraps errors of queries run through DB and Tx in a
+	// QueryError, adding the query's SQL to the message. Argument values are
+	// not included.
+	QueryInErrors bool
 }
 
 // DefaultConfig returns a default configuration for MySQL.
@@ -184,6 +220,10 @@ func (c Config) Validate() error {
 		return fmt.Errorf("%w: driver is required", ErrInvalidConfig)
 	}
 
+	if lookupDialect(c.Driver) == nil {
+		return fmt.Errorf("%w: driver %q has no registered dialect (see RegisterDialect): %w", ErrInvalidConfig, c.Driver, ErrDriverNotSupported)
+	}
+
 	if c.DSN == "" {
 		return fmt.Errorf("%w: DSN is required", ErrInvalidConfig)
 	}
@@ -228,6 +268,10 @@ func (c Config) Validate() error {
 		return fmt.Errorf("%w: RetryDelay must be >= 0", ErrInvalidConfig)
 	}
 
+	if c.MaxPlaceholders < 0 {
+		return fmt.Errorf("%w: MaxPlaceholders must be >= 0", ErrInvalidConfig)
+	}
+
 	return nil
 }
 
@@ -237,6 +281,18 @@ func (c Config) WithDSN(dsn string) Config {
 	return c
 }
 
+// WithLogger returns a copy of the config with the given query logger.
+func (c Config) WithLogger(logger QueryLogger) Config {
+	c.Logger = logger
+	return c
+}
+
+// WithQueryInErrors returns a copy of the config with QueryInErrors set.
+func (c Config) WithQueryInErrors(enabled bool) Config {
+	c.QueryInErrors = enabled
+	return c
+}
+
 // WithDriver returns a copy of the config with the given driver.
 func (c Config) WithDriver(driver Driver) Config {
 	c.Driver = driver
@@ -286,6 +342,24 @@ func (c Config) WithRetries(maxRetries int, delay time.Duration) Config {
 	return c
 }
 
+// WithMaxPlaceholders returns a copy of the config with the given MaxPlaceholders.
+func (c Config) WithMaxPlaceholders(n int) Config {
+	c.MaxPlaceholders = n
+	return c
+}
+
+// WithPrimaryKey returns a copy of the config with the given PrimaryKey.
+func (c Config) WithPrimaryKey(column string) Config {
+	c.PrimaryKey = column
+	return c
+}
+
+// WithTimeSettings returns a copy of the config with the given time settings.
+func (c Config) WithTimeSettings(settings TimeSettings) Config {
+	c.Time = settings
+	return c
+}
+
 // ConfigMap is a map of connection names to configurations.
 type ConfigMap map[string]Config
 
@@ -308,8 +382,9 @@ func (cm ConfigMap) Validate() error {
 	return nil
 }
 
-// Executor defines the interface for executing SQL operations.
-type Executor interface {
+// Querier defines the interface for running SQL statements. It is
+// implemented by both DB and Tx.
+type Querier interface {
 	// Exec executes a query without returning any rows.
 	Exec(ctx context.Context, query string, args ...any) (sql.Result, error)
 
@@ -318,6 +393,11 @@ type Executor interface {
 
 	// QueryRow executes a query that is expected to return at most one row.
 	QueryRow(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Executor defines the interface for executing SQL operations.
+type Executor interface {
+	Querier
 
 	// BeginTx starts a transaction.
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
@@ -331,16 +411,16 @@ type CRUDExecutor interface {
 	Insert(ctx context.Context, table string, data map[string]any) (sql.Result, error)
 
 	// Update updates rows in the specified table.
-	Update(ctx context.Context, table string, data, where map[string]any) (sql.Result, error)
+	Update(ctx context.Context, table string, data map[string]any, where Condition) (sql.Result, error)
 
 	// Delete deletes rows from the specified table.
-	Delete(ctx context.Context, table string, where map[string]any) (sql.Result, error)
+	Delete(ctx context.Context, table string, where Condition) (sql.Result, error)
 
 	// Select executes a SELECT query.
-	Select(ctx context.Context, table string, columns []string, where map[string]any) (*sql.Rows, error)
+	Select(ctx context.Context, table string, columns []string, where Condition) (*sql.Rows, error)
 
 	// SelectOne executes a SELECT query that returns at most one row.
-	SelectOne(ctx context.Context, table string, columns []string, where map[string]any) *sql.Row
+	SelectOne(ctx context.Context, table string, columns []string, where Condition) (*sql.Row, error)
 }
 
 // DB represents a database connection with enhanced functionality.
@@ -394,7 +474,13 @@ func (db *DB) Exec(ctx context.Context, query string, args ...any) (sql.Result,
 	ctx, cancel := db.withTimeout(ctx, db.config.QueryTimeout)
 	defer cancel()
 
-	return db.db.ExecContext(ctx, query, args...)
+	args = db.config.Time.writeArgs(args)
+	start := time.Now()
+	result, err := db.db.ExecContext(ctx, query, args...)
+	if err = db.finishQuery(ctx, query, args, start, err); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Query executes a query that returns rows.
@@ -410,7 +496,13 @@ func (db *DB) Query(ctx context.Context, query string, args ...any) (*sql.Rows,
 	ctx, cancel := db.withTimeout(ctx, db.config.QueryTimeout)
 	defer cancel()
 
-	return db.db.QueryContext(ctx, query, args...)
+	args = db.config.Time.writeArgs(args)
+	start := time.Now()
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err = db.finishQuery(ctx, query, args, start, err); err != nil {
+		return nil, err
+	}
+	return rows, nil
 }
 
 // QueryRow executes a query that is expected to return at most one row.
@@ -423,7 +515,11 @@ func (db *DB) QueryRow(ctx context.Context, query string, args ...any) *sql.Row
 	ctx, cancel := db.withTimeout(ctx, db.config.QueryTimeout)
 	defer cancel()
 
-	return db.db.QueryRowContext(ctx, query, args...)
+	args = db.config.Time.writeArgs(args)
+	start := time.Now()
+	row := db.db.QueryRowContext(ctx, query, args...)
+	db.finishQuery(ctx, query, args, start, row.Err())
+	return row
 }
 
 // BeginTx starts a transaction.
@@ -450,21 +546,8 @@ func (db *DB) Transaction(ctx context.Context, fn func(*sql.Tx) error, opts *sql
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 
-	// Execute the function
-	if err := fn(tx); err != nil {
-		// Rollback on error
-		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
-			return fmt.Errorf("%w: %v (rollback error: %v)", ErrTransactionFailed, err, rbErr)
-		}
-		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
-	}
-
-	// Commit the transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
-	}
-
-	return nil
+	// Execute the function, then commit or roll back
+	return endTx(tx, fn(tx))
 }
 
 // Insert inserts a row into the specified table.
@@ -494,11 +577,307 @@ func (db *DB) Insert(ctx context.Context, table string, data map[string]any) (sq
 		strings.Join(placeholders, ", "),
 	)
 
+	return db.Exec(ctx, db.Rebind(query), args...)
+}
+
+// InsertMany inserts multiple rows with multi-row INSERT statements and
+// returns the total number of rows affected. All rows must have the same
+// columns. Rows are split into chunks so that no statement exceeds the
+// driver's placeholder limit; when more than one chunk is needed they are
+// executed in a single transaction.
+func (db *DB) InsertMany(ctx context.Context, table string, rows []Map) (int64, error) {
+	if table == "" {
+		return 0, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("%w: no rows to insert", ErrInvalidArguments)
+	}
+
+	columns, err := insertManyColumns(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	// Some dialects also cap the rows of a single VALUES list.
+	chunks, err := chunkInsertRows(rows, columns, db.maxPlaceholders(), maxInsertRows(db.config.Driver))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(chunks) == 1 {
+		query, args, err := buildInsertManyWithDriver(db.config.Driver, table, columns, rows)
+		if err != nil {
+			return 0, fmt.Errorf("build insert query: %w", err)
+		}
+		result, err := db.Exec(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	if db.db == nil {
+		return 0, ErrConnectionClosed
+	}
+
+	ctx, cancel := db.withTimeout(ctx, db.config.TransactionTimeout)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	insertChunk := func(chunk []Map) (int64, error) {
+		query, args, err := buildInsertManyWithDriver(db.config.Driver, table, columns, chunk)
+		if err != nil {
+			return 0, fmt.Errorf("build insert query: %w", err)
+		}
+		args = db.config.Time.writeArgs(args)
+		start := time.Now()
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err = db.finishQuery(ctx, query, args, start, err); err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	var total int64
+	start := 0
+	for _, chunk := range chunks {
+		end := start + len(chunk)
+
+		n, err := insertChunk(chunk)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				return 0, fmt.Errorf("%w: insert rows %d-%d: %v (rollback error: %v)", ErrTransactionFailed, start, end-1, err, rbErr)
+			}
+			return 0, fmt.Errorf("%w: insert rows %d-%d: %w", ErrTransactionFailed, start, end-1, err)
+		}
+		total += n
+		start = end
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return total, nil
+}
+
+// chunkInsertRows splits rows into consecutive chunks that each bind at most
+// maxArgs arguments and, if maxRows is positive, hold at most maxRows rows.
+// An Expression value binds its own arguments instead of one placeholder, so
+// rows are measured individually.
+func chunkInsertRows(rows []Map, columns []string, maxArgs, maxRows int) ([][]Map, error) {
+	var chunks [][]Map
+	start, chunkArgs := 0, 0
+	for i, row := range rows {
+		n := 0
+		for _, column := range columns {
+			n += bindArgCount(row[column])
+		}
+		if n > maxArgs {
+			return nil, fmt.Errorf("%w: row %d binds %d arguments, exceeding the placeholder limit of %d", ErrInvalidArguments, i+1, n, maxArgs)
+		}
+
+		if i > start && (chunkArgs+n > maxArgs || maxRows > 0 && i-start >= maxRows) {
+			chunks = append(chunks, rows[start:i])
+			start, chunkArgs = i, 0
+		}
+		chunkArgs += n
+	}
+	return append(chunks, rows[start:]), nil
+}
+
+// InsertManyStructs inserts a slice of structs (or pointers to structs) using
+// the same column mapping rules as NewBinder. Fields are written as by
+// InsertStruct, except that generated keys are not written back and a zero
+// autoincrement or omitempty field must be zero in every row or in none, as
+// all rows share one column list. See InsertMany.
+func (db *DB) InsertManyStructs(ctx context.Context, table string, rows any) (int64, error) {
+	maps, err := structsToMaps(db.binder(), rows)
+	if err != nil {
+		return 0, err
+	}
+	return db.InsertMany(ctx, table, maps)
+}
+
+// InsertReturning inserts a row and returns the requested columns of the new
+// row, or all columns if none are given. Dialects with FeatureReturning use a
+// RETURNING clause. Others, such as MySQL, insert the row and select it by
+// the configured primary key column (see Config.PrimaryKey), using the key in
+// data if it is set and LastInsertId otherwise.
+func (db *DB) InsertReturning(ctx context.Context, table string, data map[string]any, returning ...string) (Map, error) {
+	if table == "" {
+		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildInsertQueryWithDriver(db.config.Driver, table, data)
+	if err != nil {
+		return nil, fmt.Errorf("build insert query: %w", err)
+	}
+
+	if !supports(db.config.Driver, FeatureReturning) && supports(db.config.Driver, FeatureLastInsertID) {
+		return db.insertThenSelect(ctx, table, data, query, args, returning)
+	}
+
+	query, err = ReturningWithDriver(db.config.Driver, query, returning...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _, err := db.QueryMaps(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// InsertReturningInto inserts a row and scans the inserted row back into
+// dest, a pointer to a struct. The returned columns are those the default
+// Binder maps for dest. See InsertReturning.
+func (db *DB) InsertReturningInto(ctx context.Context, table string, data map[string]any, dest any) error {
+	binder := db.binder()
+
+	columns, err := binder.columnNames(dest)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+
+	row, err := db.InsertReturning(ctx, table, data, columns...)
+	if err != nil {
+		return err
+	}
+
+	return binder.bindMap(row, dest)
+}
+
+// UpdateReturning updates rows and returns the requested columns of every
+// updated row, or all columns if none are given.
+// Only drivers with RETURNING support (PostgreSQL, SQLite) are supported.
+func (db *DB) UpdateReturning(ctx context.Context, table string, data map[string]any, where Condition, returning ...string) ([]Map, error) {
+	if table == "" {
+		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no data to update", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildUpdateQueryWithDriver(db.config.Driver, table, data, where)
+	if err != nil {
+		return nil, fmt.Errorf("build update query: %w", err)
+	}
+
+	query, err = ReturningWithDriver(db.config.Driver, query, returning...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _, err := db.QueryMaps(ctx, query, args...)
+	return rows, err
+}
+
+// DeleteReturning deletes rows and returns the requested columns of every
+// deleted row, or all columns if none are given.
+// Only drivers with RETURNING support (PostgreSQL, SQLite) are supported.
+func (db *DB) DeleteReturning(ctx context.Context, table string, where Condition, returning ...string) ([]Map, error) {
+	if table == "" {
+		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildDeleteQueryWithDriver(db.config.Driver, table, where)
+	if err != nil {
+		return nil, fmt.Errorf("build delete query: %w", err)
+	}
+
+	query, err = ReturningWithDriver(db.config.Driver, query, returning...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _, err := db.QueryMaps(ctx, query, args...)
+	return rows, err
+}
+
+// insertThenSelect emulates INSERT ... RETURNING for drivers that lack it by
+// reading the inserted row back through its primary key. A non-zero key in
+// data is used as given; otherwise the key is taken from LastInsertId.
+func (db *DB) insertThenSelect(ctx context.Context, table string, data map[string]any, query string, args []any, returning []string) (Map, error) {
+	result, err := db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKey := db.config.PrimaryKey
+	if primaryKey == "" {
+		primaryKey = "id"
+	}
+
+	// LastInsertId is 0 when the caller supplies the key or the table has
+	// no auto-increment column.
+	id := data[primaryKey]
+	if id == nil || reflect.ValueOf(id).IsZero() {
+		if id, err = result.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("get last insert id: %w", err)
+		}
+	}
+
+	query, selectArgs, err := BuildSelectQueryWithDriver(db.config.Driver, table, returning, Map{primaryKey: id})
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, _, err := db.QueryMaps(ctx, query, selectArgs...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// Upsert inserts a row or, if it conflicts with an existing unique key,
+// updates the given columns of the existing row.
+// See BuildUpsertQueryWithDriver for the per-driver syntax.
+func (db *DB) Upsert(ctx context.Context, table string, data map[string]any, conflictColumns, updateColumns []string) (sql.Result, error) {
+	if table == "" {
+		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildUpsertQueryWithDriver(db.config.Driver, table, data, conflictColumns, updateColumns)
+	if err != nil {
+		return nil, fmt.Errorf("build upsert query: %w", err)
+	}
+
+	return db.Exec(ctx, query, args...)
+}
+
+// InsertOrIgnore inserts a row unless it conflicts with an existing unique
+// key, in which case nothing happens and zero rows are affected.
+// See BuildInsertIgnoreQueryWithDriver for the per-driver syntax.
+func (db *DB) InsertOrIgnore(ctx context.Context, table string, data map[string]any, conflictColumns ...string) (sql.Result, error) {
+	if table == "" {
+		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildInsertIgnoreQueryWithDriver(db.config.Driver, table, data, conflictColumns)
+	if err != nil {
+		return nil, fmt.Errorf("build insert query: %w", err)
+	}
+
 	return db.Exec(ctx, query, args...)
 }
 
 // Update updates rows in the specified table.
-func (db *DB) Update(ctx context.Context, table string, data, where map[string]any) (sql.Result, error) {
+// The where argument accepts a Map (implicit AND of equalities) or any Condition.
+func (db *DB) Update(ctx context.Context, table string, data map[string]any, where Condition) (sql.Result, error) {
 	if table == "" {
 		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
 	}
@@ -507,7 +886,7 @@ func (db *DB) Update(ctx context.Context, table string, data, where map[string]a
 		return nil, fmt.Errorf("%w: no data to update", ErrInvalidArguments)
 	}
 
-	if len(where) == 0 {
+	if isEmptyCondition(db.config.Driver, where) {
 		return nil, fmt.Errorf("%w: WHERE clause is required for UPDATE", ErrInvalidArguments)
 	}
 
@@ -534,16 +913,17 @@ func (db *DB) Update(ctx context.Context, table string, data, where map[string]a
 	)
 
 	args := append(setArgs, whereArgs...)
-	return db.Exec(ctx, query, args...)
+	return db.Exec(ctx, db.Rebind(query), args...)
 }
 
 // Delete deletes rows from the specified table.
-func (db *DB) Delete(ctx context.Context, table string, where map[string]any) (sql.Result, error) {
+// The where argument accepts a Map (implicit AND of equalities) or any Condition.
+func (db *DB) Delete(ctx context.Context, table string, where Condition) (sql.Result, error) {
 	if table == "" {
 		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
 	}
 
-	if len(where) == 0 {
+	if isEmptyCondition(db.config.Driver, where) {
 		return nil, fmt.Errorf("%w: WHERE clause is required for DELETE", ErrInvalidArguments)
 	}
 
@@ -563,74 +943,33 @@ func (db *DB) Delete(ctx context.Context, table string, where map[string]any) (s
 		whereClause,
 	)
 
-	return db.Exec(ctx, query, args...)
+	return db.Exec(ctx, db.Rebind(query), args...)
 }
 
-// Select executes a SELECT query.
 // Select selects rows from the specified table.
-func (db *DB) Select(ctx context.Context, table string, columns []string, where map[string]any) (*sql.Rows, error) {
-	if table == "" {
-		return nil, fmt.Errorf("%w: table name cannot be empty", ErrInvalidArguments)
-	}
-
-	// Use driver-specific escaping for enhanced security
-	escapedTable, err := EscapeTableName(db.config.Driver, table)
-	if err != nil {
-		return nil, fmt.Errorf("escape table name %q: %w", table, err)
-	}
-
-	columnList, err := buildColumnListWithDriver(db.config.Driver, columns)
+// The where argument accepts a Map (implicit AND of equalities) or any Condition.
+// Select takes no lock options; use Tx.Select with WithLock for locking selects.
+func (db *DB) Select(ctx context.Context, table string, columns []string, where Condition) (*sql.Rows, error) {
+	query, args, err := buildSelectWithDriver(db.config.Driver, table, columns, where, selectOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("build column list: %w", err)
-	}
-
-	query := fmt.Sprintf("SELECT %s FROM %s", columnList, escapedTable)
-
-	if len(where) > 0 {
-		whereClause, args, err := buildWhereClauseWithDriver(db.config.Driver, where)
-		if err != nil {
-			return nil, fmt.Errorf("build where clause: %w", err)
-		}
-		query += " WHERE " + whereClause
-		return db.Query(ctx, query, args...)
+		return nil, err
 	}
 
-	return db.Query(ctx, query)
+	return db.Query(ctx, query, args...)
 }
 
-// SelectOne executes a SELECT query that returns at most one row.
 // SelectOne selects a single row from the specified table.
-func (db *DB) SelectOne(ctx context.Context, table string, columns []string, where map[string]any) *sql.Row {
-	if table == "" {
-		return &sql.Row{}
-	}
-
-	// Use driver-specific escaping for enhanced security
-	escapedTable, err := EscapeTableName(db.config.Driver, table)
+// The where argument accepts a Map (implicit AND of equalities) or any Condition.
+// Like Select, it takes no lock options; use Tx.SelectOne with WithLock.
+// An error is returned when the query cannot be built, e.g. for an invalid
+// column or an empty In list.
+func (db *DB) SelectOne(ctx context.Context, table string, columns []string, where Condition) (*sql.Row, error) {
+	query, args, err := buildSelectWithDriver(db.config.Driver, table, columns, where, selectOptions{})
 	if err != nil {
-		// Return empty row on error to maintain backward compatibility
-		return &sql.Row{}
-	}
-
-	columnList, err := buildColumnListWithDriver(db.config.Driver, columns)
-	if err != nil {
-		// Return empty row on error to maintain backward compatibility
-		return &sql.Row{}
-	}
-
-	query := fmt.Sprintf("SELECT %s FROM %s", columnList, escapedTable)
-
-	if len(where) > 0 {
-		whereClause, args, err := buildWhereClauseWithDriver(db.config.Driver, where)
-		if err != nil {
-			// Return empty row on error to maintain backward compatibility
-			return &sql.Row{}
-		}
-		query += " WHERE " + whereClause
-		return db.QueryRow(ctx, query, args...)
+		return nil, err
 	}
 
-	return db.QueryRow(ctx, query)
+	return db.QueryRow(ctx, query, args...), nil
 }
 
 // Ping verifies the connection is still alive.
@@ -667,11 +1006,68 @@ func (db *DB) Config() Config {
 	return db.config
 }
 
+// Rebind converts a query written with '?' placeholders into the placeholder
+// style of this connection's driver. See the package-level Rebind.
+func (db *DB) Rebind(query string) string {
+	return Rebind(db.config.Driver, query)
+}
+
+// Dialect returns the dialect registered for this connection's driver.
+func (db *DB) Dialect() (Dialect, error) {
+	return GetDialect(db.config.Driver)
+}
+
 // RawDB returns the underlying sql.DB.
 func (db *DB) RawDB() *sql.DB {
 	return db.db
 }
 
+// binder returns a default Binder with the configured time settings.
+func (db *DB) binder() *Binder {
+	return NewBinder().WithTimeSettings(db.config.Time)
+}
+
+// queryContext runs a query under the query timeout. Unlike Query, the
+// timeout stays in effect while the rows are read; the caller must call the
+// returned cancel function once the rows are closed.
+func (db *DB) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, context.CancelFunc, error) {
+	if db.db == nil {
+		return nil, nil, ErrConnectionClosed
+	}
+
+	if query == "" {
+		return nil, nil, ErrInvalidQuery
+	}
+
+	ctx, cancel := db.withTimeout(ctx, db.config.QueryTimeout)
+	args = db.config.Time.writeArgs(args)
+	start := time.Now()
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err = db.finishQuery(ctx, query, args, start, err); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return rows, cancel, nil
+}
+
+// finishQuery applies the configured query hooks to a finished query.
+func (db *DB) finishQuery(ctx context.Context, query string, args []any, start time.Time, err error) error {
+	return db.hooks().finish(ctx, query, args, start, err)
+}
+
+// hooks returns the query hooks of the configuration.
+func (db *DB) hooks() queryHooks {
+	return queryHooks{logger: db.config.Logger, queryInErrors: db.config.QueryInErrors}
+}
+
+// maxPlaceholders returns the bind parameter limit for a single statement.
+func (db *DB) maxPlaceholders() int {
+	if db.config.MaxPlaceholders > 0 {
+		return db.config.MaxPlaceholders
+	}
+	return defaultMaxPlaceholders(db.config.Driver)
+}
+
 // withTimeout adds a timeout to the context if configured.
 func (db *DB) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout > 0 {
@@ -698,55 +1094,124 @@ func buildInsertData(data map[string]any) (columns []string, placeholders []stri
 }
 
 // buildInsertDataWithDriver builds INSERT query components with driver-specific escaping.
+// Columns are emitted in sorted order and Expression values are inlined in
+// place of their placeholder.
 func buildInsertDataWithDriver(driver Driver, data map[string]any) (columns []string, placeholders []string, args []any, err error) {
 	columns = make([]string, 0, len(data))
 	placeholders = make([]string, 0, len(data))
 	args = make([]any, 0, len(data))
 
-	for column, value := range data {
+	keys := Map(data).Keys()
+	sort.Strings(keys)
+	for _, column := range keys {
+		value := data[column]
 		escaped, err := EscapeColumnName(driver, column)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("escape column %q: %w", column, err)
 		}
+		sql, valueArgs, err := bindValue(driver, value)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("column %q: %w", column, err)
+		}
 		columns = append(columns, escaped)
-		placeholders = append(placeholders, "?")
-		args = append(args, value)
+		placeholders = append(placeholders, sql)
+		args = append(args, valueArgs...)
 	}
 
 	return columns, placeholders, args, nil
 }
 
-// buildSetClause builds SET clause for UPDATE queries.
+// insertManyColumns returns the sorted column set shared by all rows,
+// or an error if any row has a different set of columns.
+func insertManyColumns(rows []Map) ([]string, error) {
+	columns := rows[0].Keys()
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: row 0 has no columns", ErrInvalidArguments)
+	}
+	sort.Strings(columns)
 
-// buildWhereClauseWithDriver builds WHERE clause components with driver-specific escaping.
-func buildWhereClauseWithDriver(driver Driver, where map[string]any) (clause string, args []any, err error) {
-	clauses := make([]string, 0, len(where))
-	args = make([]any, 0, len(where))
+	for i, row := range rows[1:] {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("%w: row %d has %d columns, expected %d", ErrInvalidArguments, i+1, len(row), len(columns))
+		}
+		for _, column := range columns {
+			if _, ok := row[column]; !ok {
+				return nil, fmt.Errorf("%w: row %d is missing column %q", ErrInvalidArguments, i+1, column)
+			}
+		}
+	}
 
-	for column, value := range where {
-		escaped, err := EscapeColumnName(driver, column)
-		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
+	return columns, nil
+}
+
+// buildInsertManyWithDriver builds a multi-row INSERT for the given columns,
+// rebound to the driver's placeholder style.
+func buildInsertManyWithDriver(driver Driver, table string, columns []string, rows []Map) (string, []any, error) {
+	escapedTable, err := EscapeTableName(driver, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
+	}
+
+	escapedColumns, err := escapeColumnsWithDriver(driver, columns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	values := make([]string, len(rows))
+	args := make([]any, 0, len(rows)*len(columns))
+	placeholders := make([]string, len(columns))
+
+	for i, row := range rows {
+		for j, column := range columns {
+			sql, valueArgs, err := bindValue(driver, row[column])
+			if err != nil {
+				return "", nil, fmt.Errorf("row %d column %q: %w", i+1, column, err)
+			}
+			placeholders[j] = sql
+			args = append(args, valueArgs...)
 		}
-		clauses = append(clauses, fmt.Sprintf("%s = ?", escaped))
-		args = append(args, value)
+		values[i] = "(" + strings.Join(placeholders, ", ") + ")"
 	}
 
-	return strings.Join(clauses, " AND "), args, nil
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		escapedTable,
+		strings.Join(escapedColumns, ", "),
+		strings.Join(values, ", "),
+	)
+
+	return Rebind(driver, query), args, nil
+}
+
+// buildWhereClauseWithDriver builds WHERE clause components with driver-specific escaping.
+// An empty clause is returned for a nil or empty condition.
+func buildWhereClauseWithDriver(driver Driver, where Condition) (clause string, args []any, err error) {
+	if where == nil {
+		return "", nil, nil
+	}
+	return where.ToSQL(driver)
 }
 
 // buildSetClauseWithDriver builds UPDATE SET clause components with driver-specific escaping.
+// Columns are emitted in sorted order and Expression values are inlined, as
+// in "counter = counter + ?".
 func buildSetClauseWithDriver(driver Driver, data map[string]any) (clause string, args []any, err error) {
 	clauses := make([]string, 0, len(data))
 	args = make([]any, 0, len(data))
 
-	for column, value := range data {
+	keys := Map(data).Keys()
+	sort.Strings(keys)
+	for _, column := range keys {
+		value := data[column]
 		escaped, err := EscapeColumnName(driver, column)
 		if err != nil {
 			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
 		}
-		clauses = append(clauses, fmt.Sprintf("%s = ?", escaped))
-		args = append(args, value)
+		sql, valueArgs, err := bindValue(driver, value)
+		if err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", column, err)
+		}
+		clauses = append(clauses, fmt.Sprintf("%s = %s", escaped, sql))
+		args = append(args, valueArgs...)
 	}
 
 	return strings.Join(clauses, ", "), args, nil
@@ -783,6 +1248,7 @@ func buildColumnList(columns []string) string {
 }
 
 // buildColumnListWithDriver builds a column list for SELECT queries with driver-specific escaping.
+// Columns may be qualified, and "*" or "t.*" select all columns.
 func buildColumnListWithDriver(driver Driver, columns []string) (string, error) {
 	if len(columns) == 0 {
 		return "*", nil
@@ -790,7 +1256,7 @@ func buildColumnListWithDriver(driver Driver, columns []string) (string, error)
 
 	escaped := make([]string, len(columns))
 	for i, col := range columns {
-		escapedCol, err := EscapeColumnName(driver, col)
+		escapedCol, err := EscapeColumnNameWithStar(driver, col)
 		if err != nil {
 			return "", fmt.Errorf("escape column %q: %w", col, err)
 		}
@@ -799,6 +1265,19 @@ func buildColumnListWithDriver(driver Driver, columns []string) (string, error)
 	return strings.Join(escaped, ", "), nil
 }
 
+// escapeColumnsWithDriver escapes each column name for the driver.
+func escapeColumnsWithDriver(driver Driver, columns []string) ([]string, error) {
+	escaped := make([]string, len(columns))
+	for i, col := range columns {
+		escapedCol, err := EscapeColumnName(driver, col)
+		if err != nil {
+			return nil, fmt.Errorf("escape column %q: %w", col, err)
+		}
+		escaped[i] = escapedCol
+	}
+	return escaped, nil
+}
+
 // escapeIdentifier escapes SQL identifiers to prevent SQL injection.
 // Uses the new security module for enhanced protection.
 func escapeIdentifier(identifier string) string {
@@ -943,7 +1422,7 @@ func IsDuplicateError(err error) bool {
 	}
 
 	// Check error message for duplicate entry
-	errStr := err.Error()
+	errStr := errorText(err)
 	return strings.Contains(errStr, "Duplicate entry") ||
 		strings.Contains(errStr, "1062") ||
 		strings.Contains(errStr, "unique constraint")
@@ -956,7 +1435,7 @@ func IsForeignKeyError(err error) bool {
 	}
 
 	// Check error message for foreign key
-	errStr := err.Error()
+	errStr := errorText(err)
 	return strings.Contains(errStr, "foreign key constraint") ||
 		strings.Contains(errStr, "1452")
 }
@@ -1246,7 +1725,7 @@ func Insert(ctx context.Context, name, table string, data Map) (sql.Result, erro
 }
 
 // Update updates rows in the specified table.
-func Update(ctx context.Context, name, table string, data, where Map) (sql.Result, error) {
+func Update(ctx context.Context, name, table string, data Map, where Condition) (sql.Result, error) {
 	db, err := GetDB(name)
 	if err != nil {
 		return nil, err
@@ -1255,7 +1734,7 @@ func Update(ctx context.Context, name, table string, data, where Map) (sql.Resul
 }
 
 // Delete deletes rows from the specified table.
-func Delete(ctx context.Context, name, table string, where Map) (sql.Result, error) {
+func Delete(ctx context.Context, name, table string, where Condition) (sql.Result, error) {
 	db, err := GetDB(name)
 	if err != nil {
 		return nil, err
@@ -1264,7 +1743,7 @@ func Delete(ctx context.Context, name, table string, where Map) (sql.Result, err
 }
 
 // Select executes a SELECT query.
-func Select(ctx context.Context, name, table string, columns []string, where Map) (*sql.Rows, error) {
+func Select(ctx context.Context, name, table string, columns []string, where Condition) (*sql.Rows, error) {
 	db, err := GetDB(name)
 	if err != nil {
 		return nil, err
@@ -1273,11 +1752,10 @@ func Select(ctx context.Context, name, table string, columns []string, where Map
 }
 
 // SelectOne executes a SELECT query that returns at most one row.
-func SelectOne(ctx context.Context, name, table string, columns []string, where Map) *sql.Row {
+func SelectOne(ctx context.Context, name, table string, columns []string, where Condition) (*sql.Row, error) {
 	db, err := GetDB(name)
 	if err != nil {
-		// Return a row that will error when scanned
-		return &sql.Row{}
+		return nil, err
 	}
 	return db.SelectOne(ctx, table, columns, where)
 }
@@ -3,7 +3,10 @@ package sqlx_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,6 +57,14 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "driver without dialect",
+			config: sqlx.Config{
+				Driver: "unknown",
+				DSN:    "test:test@tcp(localhost:3306)/test",
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty DSN",
 			config: sqlx.Config{
@@ -479,6 +490,31 @@ func TestCountQuery(t *testing.T) {
 			input:    "SELECT id FROM users LIMIT 10",
 			expected: "SELECT COUNT(*) FROM users",
 		},
+		{
+			name:     "column named like a keyword",
+			input:    "SELECT from_date, limit_value FROM events WHERE note = 'ORDER BY x' ORDER BY from_date",
+			expected: "SELECT COUNT(*) FROM events WHERE note = 'ORDER BY x'",
+		},
+		{
+			name:     "subquery in select list",
+			input:    "SELECT id, (SELECT MAX(total) FROM orders o WHERE o.user_id = u.id LIMIT 1) FROM users u",
+			expected: "SELECT COUNT(*) FROM users u",
+		},
+		{
+			name:     "group by is wrapped",
+			input:    "SELECT user_id, COUNT(*) FROM orders GROUP BY user_id ORDER BY 2 DESC LIMIT 5",
+			expected: "SELECT COUNT(*) FROM (SELECT user_id, COUNT(*) FROM orders GROUP BY user_id) t",
+		},
+		{
+			name:     "distinct is wrapped",
+			input:    "select distinct email from users",
+			expected: "SELECT COUNT(*) FROM (select distinct email from users) t",
+		},
+		{
+			name:     "not a select",
+			input:    "DELETE FROM users",
+			expected: "DELETE FROM users",
+		},
 	}
 
 	for _, tt := range tests {
@@ -542,3 +578,316 @@ func TestConfigMapValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		input    string
+		expected string
+	}{
+		{
+			name:     "mysql unchanged",
+			driver:   sqlx.MySQL,
+			input:    "SELECT * FROM users WHERE id = ? AND status = ?",
+			expected: "SELECT * FROM users WHERE id = ? AND status = ?",
+		},
+		{
+			name:     "sqlite unchanged",
+			driver:   sqlx.SQLite,
+			input:    "SELECT * FROM users WHERE id = ?",
+			expected: "SELECT * FROM users WHERE id = ?",
+		},
+		{
+			name:     "postgres numbered",
+			driver:   sqlx.PostgreSQL,
+			input:    "SELECT * FROM users WHERE id = ? AND status = ?",
+			expected: "SELECT * FROM users WHERE id = $1 AND status = $2",
+		},
+		{
+			name:     "postgres skips string literals",
+			driver:   sqlx.PostgreSQL,
+			input:    "SELECT '?', 'it''s ?' FROM users WHERE id = ?",
+			expected: "SELECT '?', 'it''s ?' FROM users WHERE id = $1",
+		},
+		{
+			name:     "postgres skips quoted identifiers",
+			driver:   sqlx.PostgreSQL,
+			input:    `SELECT "what?" FROM users WHERE id = ?`,
+			expected: `SELECT "what?" FROM users WHERE id = $1`,
+		},
+		{
+			name:     "postgres skips comments",
+			driver:   sqlx.PostgreSQL,
+			input:    "SELECT 1 -- why?\nFROM users /* really? */ WHERE id = ?",
+			expected: "SELECT 1 -- why?\nFROM users /* really? */ WHERE id = $1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sqlx.Rebind(tt.driver, tt.input)
+			if result != tt.expected {
+				t.Errorf("Rebind() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryBuilderWithDriverPlaceholders(t *testing.T) {
+	query, args, err := sqlx.BuildUpdateQueryWithDriver(sqlx.PostgreSQL, "users",
+		map[string]any{"age": 31}, sqlx.Map{"id": 1})
+	if err != nil {
+		t.Fatalf("BuildUpdateQueryWithDriver failed: %v", err)
+	}
+	expected := `UPDATE "users" SET "age" = $1 WHERE "id" = $2`
+	if query != expected {
+		t.Errorf("Expected update query %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 update args, got %d", len(args))
+	}
+
+	query, _, err = sqlx.BuildSelectQueryWithDriver(sqlx.MySQL, "users", []string{"id"}, sqlx.Map{"id": 1})
+	if err != nil {
+		t.Fatalf("BuildSelectQueryWithDriver failed: %v", err)
+	}
+	expected = "SELECT `id` FROM `users` WHERE `id` = ?"
+	if query != expected {
+		t.Errorf("Expected select query %q, got %q", expected, query)
+	}
+}
+
+func TestBuildInsertManyQueryWithDriver(t *testing.T) {
+	rows := []sqlx.Map{
+		{"name": "John", "age": 30},
+		{"age": 25, "name": "Jane"},
+	}
+
+	query, args, err := sqlx.BuildInsertManyQueryWithDriver(sqlx.PostgreSQL, "users", rows)
+	if err != nil {
+		t.Fatalf("BuildInsertManyQueryWithDriver failed: %v", err)
+	}
+	expected := `INSERT INTO "users" ("age", "name") VALUES ($1, $2), ($3, $4)`
+	if query != expected {
+		t.Errorf("Expected insert query %q, got %q", expected, query)
+	}
+	expectedArgs := []any{30, "John", 25, "Jane"}
+	for i, arg := range args {
+		if arg != expectedArgs[i] {
+			t.Errorf("args[%d] = %v, want %v", i, arg, expectedArgs[i])
+		}
+	}
+
+	_, _, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.MySQL, "users", []sqlx.Map{
+		{"name": "John", "age": 30},
+		{"name": "Jane", "email": "jane@example.com"},
+	})
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for mismatched columns, got %v", err)
+	}
+
+	_, _, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.MySQL, "users", nil)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments for no rows, got %v", err)
+	}
+
+	tooMany := make([]sqlx.Map, 1001)
+	for i := range tooMany {
+		tooMany[i] = sqlx.Map{"id": i}
+	}
+	if _, _, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.SQLServer, "users", tooMany); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments above the SQL Server row limit, got %v", err)
+	}
+	if _, _, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.SQLServer, "users", tooMany[:1000]); err != nil {
+		t.Errorf("BuildInsertManyQueryWithDriver at the SQL Server row limit failed: %v", err)
+	}
+
+	_, _, err = sqlx.BuildInsertManyQueryWithDriver(sqlx.MySQL, "users", []sqlx.Map{{"*": 1}})
+	if err == nil {
+		t.Error("Expected an error for a * insert column")
+	}
+}
+
+func TestInsertManyRespectsRowLimit(t *testing.T) {
+	values := make([]string, 1000)
+	for i := range values {
+		values[i] = fmt.Sprintf("(@p%d)", i+1)
+	}
+	db := openFakeSQLX(t, sqlx.SQLServer, map[string]fakeResult{
+		"INSERT INTO [tags] ([name]) VALUES " + strings.Join(values, ", "): {rowsAffected: 1000},
+		"INSERT INTO [tags] ([name]) VALUES (@p1)":                         {rowsAffected: 1},
+	})
+
+	rows := make([]sqlx.Map, 1001)
+	for i := range rows {
+		rows[i] = sqlx.Map{"name": i}
+	}
+	if n, err := db.InsertMany(context.Background(), "tags", rows); err != nil || n != 1001 {
+		t.Errorf("InsertMany() = %d, %v, want 1001 rows in two statements", n, err)
+	}
+}
+
+func TestInsertManySQLServerParameterLimit(t *testing.T) {
+	// 700 rows of 3 columns are 2100 parameters, two more than sp_executesql
+	// leaves for the statement, so the last row goes into a second statement.
+	values := make([]string, 699)
+	for i := range values {
+		values[i] = fmt.Sprintf("(@p%d, @p%d, @p%d)", 3*i+1, 3*i+2, 3*i+3)
+	}
+	db := openFakeSQLX(t, sqlx.SQLServer, map[string]fakeResult{
+		"INSERT INTO [points] ([a], [b], [c]) VALUES " + strings.Join(values, ", "): {rowsAffected: 699},
+		"INSERT INTO [points] ([a], [b], [c]) VALUES (@p1, @p2, @p3)":               {rowsAffected: 1},
+	})
+
+	rows := make([]sqlx.Map, 700)
+	for i := range rows {
+		rows[i] = sqlx.Map{"a": i, "b": i, "c": i}
+	}
+	if n, err := db.InsertMany(context.Background(), "points", rows); err != nil || n != 700 {
+		t.Errorf("InsertMany() = %d, %v, want 700 rows in statements of at most 2098 parameters", n, err)
+	}
+}
+
+func TestBuildUpsertQueryWithDriver(t *testing.T) {
+	data := map[string]any{"email": "john@example.com", "name": "John", "visits": 1}
+
+	tests := []struct {
+		name     string
+		driver   sqlx.Driver
+		update   []string
+		expected string
+	}{
+		{
+			name:     "mysql",
+			driver:   sqlx.MySQL,
+			expected: "INSERT INTO `users` (`email`, `name`, `visits`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `visits` = VALUES(`visits`)",
+		},
+		{
+			name:     "postgres",
+			driver:   sqlx.PostgreSQL,
+			expected: `INSERT INTO "users" ("email", "name", "visits") VALUES ($1, $2, $3) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "visits" = EXCLUDED."visits"`,
+		},
+		{
+			name:     "sqlite explicit update columns",
+			driver:   sqlx.SQLite,
+			update:   []string{"visits"},
+			expected: `INSERT INTO "users" ("email", "name", "visits") VALUES (?, ?, ?) ON CONFLICT ("email") DO UPDATE SET "visits" = EXCLUDED."visits"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := sqlx.BuildUpsertQueryWithDriver(tt.driver, "users", data, []string{"email"}, tt.update)
+			if err != nil {
+				t.Fatalf("BuildUpsertQueryWithDriver failed: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("BuildUpsertQueryWithDriver() = %q, want %q", query, tt.expected)
+			}
+			if len(args) != 3 {
+				t.Errorf("Expected 3 args, got %d", len(args))
+			}
+		})
+	}
+
+	_, _, err := sqlx.BuildUpsertQueryWithDriver(sqlx.PostgreSQL, "users", data, nil, nil)
+	if !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("Expected ErrInvalidArguments without conflict columns, got %v", err)
+	}
+}
+
+func TestBuildInsertIgnoreQueryWithDriver(t *testing.T) {
+	data := map[string]any{"email": "john@example.com"}
+
+	query, _, err := sqlx.BuildInsertIgnoreQueryWithDriver(sqlx.MySQL, "users", data, nil)
+	if err != nil {
+		t.Fatalf("BuildInsertIgnoreQueryWithDriver failed: %v", err)
+	}
+	expected := "INSERT IGNORE INTO `users` (`email`) VALUES (?)"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+
+	query, _, err = sqlx.BuildInsertIgnoreQueryWithDriver(sqlx.PostgreSQL, "users", data, []string{"email"})
+	if err != nil {
+		t.Fatalf("BuildInsertIgnoreQueryWithDriver failed: %v", err)
+	}
+	expected = `INSERT INTO "users" ("email") VALUES ($1) ON CONFLICT ("email") DO NOTHING`
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+}
+
+func TestReturningWithDriver(t *testing.T) {
+	query, _, err := sqlx.BuildUpdateQueryWithDriver(sqlx.PostgreSQL, "users",
+		map[string]any{"name": "John"}, sqlx.Col("id").Eq(1))
+	if err != nil {
+		t.Fatalf("BuildUpdateQueryWithDriver failed: %v", err)
+	}
+
+	returning, err := sqlx.ReturningWithDriver(sqlx.PostgreSQL, query, "id", "updated_at")
+	if err != nil {
+		t.Fatalf("ReturningWithDriver failed: %v", err)
+	}
+	expected := `UPDATE "users" SET "name" = $1 WHERE "id" = $2 RETURNING "id", "updated_at"`
+	if returning != expected {
+		t.Errorf("Expected query %q, got %q", expected, returning)
+	}
+
+	returning, err = sqlx.ReturningWithDriver(sqlx.SQLite, `DELETE FROM "users" WHERE "id" = ?`)
+	if err != nil {
+		t.Fatalf("ReturningWithDriver failed: %v", err)
+	}
+	expected = `DELETE FROM "users" WHERE "id" = ? RETURNING *`
+	if returning != expected {
+		t.Errorf("Expected query %q, got %q", expected, returning)
+	}
+
+	_, err = sqlx.ReturningWithDriver(sqlx.MySQL, "DELETE FROM `users`")
+	if !errors.Is(err, sqlx.ErrDriverNotSupported) {
+		t.Errorf("Expected ErrDriverNotSupported for MySQL, got %v", err)
+	}
+
+	_, err = sqlx.ReturningWithDriver(sqlx.PostgreSQL, query, "id; --")
+	if !errors.Is(err, sqlx.ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+func TestSelectOneBuildError(t *testing.T) {
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{})
+
+	row, err := db.SelectOne(context.Background(), "users", []string{"id"}, sqlx.Col("id").In([]int{}))
+	if row != nil || !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("SelectOne() = %v, %v, want ErrInvalidArguments", row, err)
+	}
+}
+
+func TestInsertReturningWithoutReturning(t *testing.T) {
+	ctx := context.Background()
+	selected := fakeResult{
+		columns: []string{"id", "name"},
+		types:   []string{"BIGINT", "VARCHAR"},
+		rows:    [][]driver.Value{{int64(7), "Alice"}},
+	}
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"INSERT INTO `users` (`id`, `name`) VALUES (?, ?)": {rowsAffected: 1},
+		"INSERT INTO `users` (`name`) VALUES (?)":          {lastInsertID: 42, rowsAffected: 1},
+		"SELECT `id`, `name` FROM `users` WHERE `id` = ?":  selected,
+	})
+
+	if _, err := db.InsertReturning(ctx, "users", map[string]any{"id": 7, "name": "Alice"}, "id", "name"); err != nil {
+		t.Fatalf("InsertReturning() with an explicit key error = %v", err)
+	}
+	if args := fakeLastArgs(); len(args) != 1 || args[0] != int64(7) {
+		t.Errorf("select args = %v, want the supplied key 7", args)
+	}
+
+	if _, err := db.InsertReturning(ctx, "users", map[string]any{"name": "Bob"}, "id", "name"); err != nil {
+		t.Fatalf("InsertReturning() error = %v", err)
+	}
+	if args := fakeLastArgs(); len(args) != 1 || args[0] != int64(42) {
+		t.Errorf("select args = %v, want the generated key 42", args)
+	}
+}
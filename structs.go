@@ -0,0 +1,283 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Struct-driven writes read the same `db` tags as Binder, with these options:
+//
+//	pk             the field is part of the primary key used by UpdateStruct and DeleteStruct
+//	autoincrement  the database generates the value; a zero value is not inserted
+//	               and the generated key is written back by InsertStruct
+//	omitempty      a zero value is not written
+//	readonly       the field is never written, e.g. a column with a database default
+//	json           the field is written as JSON text and decoded from it by Binder
+//
+// For example:
+//
+//	type User struct {
+//		ID        int64     `db:"id,pk,autoincrement"`
+//		Name      string    `db:"name"`
+//		CreatedAt time.Time `db:"created_at,readonly"`
+//	}
+
+// InsertStruct inserts a row built from the fields of src, a struct or a
+// pointer to a struct. If src is a pointer and has a zero autoincrement
+// field, the generated key is written back to it, using RETURNING where the
+// dialect supports it and LastInsertId otherwise.
+func (db *DB) InsertStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return insertStruct(ctx, db, db.config.Driver, table, src)
+}
+
+// UpdateStruct updates the row identified by the pk fields of src with its
+// other writable fields. A zero pk field is treated as not set and rejected
+// with ErrInvalidArguments.
+func (db *DB) UpdateStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return updateStruct(ctx, db, db.config.Driver, table, src)
+}
+
+// DeleteStruct deletes the row identified by the pk fields of src. A zero pk
+// field is rejected with ErrInvalidArguments.
+func (db *DB) DeleteStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return deleteStruct(ctx, db, db.config.Driver, table, src)
+}
+
+// InsertStruct inserts a row built from src in the transaction. See DB.InsertStruct.
+func (tx *Tx) InsertStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return insertStruct(ctx, tx, tx.driver, table, src)
+}
+
+// UpdateStruct updates the row identified by src in the transaction. See DB.UpdateStruct.
+func (tx *Tx) UpdateStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return updateStruct(ctx, tx, tx.driver, table, src)
+}
+
+// DeleteStruct deletes the row identified by src in the transaction. See DB.DeleteStruct.
+func (tx *Tx) DeleteStruct(ctx context.Context, table string, src any) (sql.Result, error) {
+	return deleteStruct(ctx, tx, tx.driver, table, src)
+}
+
+// insertStruct implements InsertStruct for a DB or Tx.
+func insertStruct(ctx context.Context, q Querier, driver Driver, table string, src any) (sql.Result, error) {
+	structVal, fields, err := writeFields(src)
+	if err != nil {
+		return nil, err
+	}
+
+	data, generated, err := insertData(structVal, fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no columns to insert", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildInsertQueryWithDriver(driver, table, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key can only be written back through a pointer.
+	if generated == nil || !structVal.CanAddr() {
+		return q.Exec(ctx, query, args...)
+	}
+	key := fieldByIndex(structVal, generated.Index)
+
+	if supports(driver, FeatureReturning) {
+		return insertReturningKey(ctx, q, driver, query, args, generated.Name, key)
+	}
+
+	result, err := q.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("read generated key: %w", err)
+	}
+	if err := assignValue(key, id); err != nil {
+		return nil, fmt.Errorf("field %s: %w", generated.Name, err)
+	}
+	return result, nil
+}
+
+// insertData returns the columns inserted for a struct value. Readonly fields
+// are left out, as are zero autoincrement and omitempty fields. The first
+// zero autoincrement field is returned as the key the database generates.
+func insertData(structVal reflect.Value, fields []structField) (Map, *structField, error) {
+	data := make(Map, len(fields))
+	var generated *structField
+	for i, field := range fields {
+		if field.ReadOnly {
+			continue
+		}
+
+		value, zero, err := field.value(structVal)
+		if err != nil {
+			return nil, nil, err
+		}
+		if field.AutoIncrement && zero {
+			if generated == nil {
+				generated = &fields[i]
+			}
+			continue
+		}
+		if field.OmitEmpty && zero {
+			continue
+		}
+		data[field.Name] = value
+	}
+	return data, generated, nil
+}
+
+// insertReturningKey runs an insert with a RETURNING clause for the key
+// column and stores the returned key in the key field.
+func insertReturningKey(ctx context.Context, q Querier, driver Driver, query string, args []any, column string, key reflect.Value) (sql.Result, error) {
+	query, err := ReturningWithDriver(driver, query, column)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cancel, err := queryRows(ctx, q, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoRows
+	}
+
+	var raw any
+	if err := rows.Scan(&raw); err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		if err := assignValue(key, raw); err != nil {
+			return nil, fmt.Errorf("field %s: %w", column, err)
+		}
+	}
+
+	return returnedKeyResult{key: raw}, rows.Close()
+}
+
+// returnedKeyResult is the sql.Result of a single-row insert whose key was
+// read from a RETURNING clause.
+type returnedKeyResult struct {
+	key any
+}
+
+// LastInsertId returns the returned key if it is an integer.
+func (r returnedKeyResult) LastInsertId() (int64, error) {
+	if r.key == nil {
+		return 0, fmt.Errorf("%w: no key returned", ErrNoRows)
+	}
+	return toInt64(reflect.ValueOf(r.key))
+}
+
+// RowsAffected returns 1.
+func (r returnedKeyResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+// updateStruct implements UpdateStruct for a DB or Tx.
+func updateStruct(ctx context.Context, q Querier, driver Driver, table string, src any) (sql.Result, error) {
+	structVal, fields, err := writeFields(src)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(Map, len(fields))
+	where := make(Map)
+	for _, field := range fields {
+		value, zero, err := field.value(structVal)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field.PrimaryKey:
+			if zero {
+				return nil, fmt.Errorf("%w: primary key field %s is not set", ErrInvalidArguments, field.Name)
+			}
+			where[field.Name] = value
+		case field.ReadOnly, field.AutoIncrement, field.OmitEmpty && zero:
+		default:
+			data[field.Name] = value
+		}
+	}
+
+	if len(where) == 0 {
+		return nil, fmt.Errorf("%w: %T has no primary key fields (tag option pk)", ErrInvalidArguments, src)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no columns to update", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildUpdateQueryWithDriver(driver, table, data, where)
+	if err != nil {
+		return nil, err
+	}
+	return q.Exec(ctx, query, args...)
+}
+
+// deleteStruct implements DeleteStruct for a DB or Tx.
+func deleteStruct(ctx context.Context, q Querier, driver Driver, table string, src any) (sql.Result, error) {
+	structVal, fields, err := writeFields(src)
+	if err != nil {
+		return nil, err
+	}
+
+	where := make(Map)
+	for _, field := range fields {
+		if !field.PrimaryKey {
+			continue
+		}
+		value, zero, err := field.value(structVal)
+		if err != nil {
+			return nil, err
+		}
+		if zero {
+			return nil, fmt.Errorf("%w: primary key field %s is not set", ErrInvalidArguments, field.Name)
+		}
+		where[field.Name] = value
+	}
+
+	if len(where) == 0 {
+		return nil, fmt.Errorf("%w: %T has no primary key fields (tag option pk)", ErrInvalidArguments, src)
+	}
+
+	query, args, err := BuildDeleteQueryWithDriver(driver, table, where)
+	if err != nil {
+		return nil, err
+	}
+	return q.Exec(ctx, query, args...)
+}
+
+// writeFields returns the struct value of src, a struct or a non-nil
+// pointer to one, and its column fields.
+func writeFields(src any) (reflect.Value, []structField, error) {
+	structVal := reflect.ValueOf(src)
+	if structVal.Kind() == reflect.Ptr {
+		if structVal.IsNil() {
+			return reflect.Value{}, nil, fmt.Errorf("%w: src is a nil pointer", ErrInvalidArguments)
+		}
+		structVal = structVal.Elem()
+	}
+	if structVal.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("%w: src must be a struct or pointer to struct, got %T", ErrInvalidArguments, src)
+	}
+
+	fields, err := NewBinder().getColumns(structVal)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return structVal, fields, nil
+}
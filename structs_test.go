@@ -0,0 +1,176 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+type structUser struct {
+	ID        int64     `db:"id,pk,autoincrement"`
+	Name      string    `db:"name"`
+	Nickname  string    `db:"nickname,omitempty"`
+	CreatedAt time.Time `db:"created_at,readonly"`
+	Secret    string    `db:"-"`
+}
+
+type structMembership struct {
+	UserID  int64  `db:"user_id,pk"`
+	GroupID int64  `db:"group_id,pk"`
+	Kind    string `db:"kind"`
+}
+
+func TestInsertStruct(t *testing.T) {
+	ctx := context.Background()
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"INSERT INTO `users` (`name`) VALUES (?)":                {lastInsertID: 42, rowsAffected: 1},
+		"INSERT INTO `users` (`id`, `name`) VALUES (?, ?)":       {rowsAffected: 1},
+		"INSERT INTO `users` (`name`, `nickname`) VALUES (?, ?)": {lastInsertID: 43, rowsAffected: 1},
+	})
+
+	user := &structUser{Name: "Alice", CreatedAt: time.Now(), Secret: "x"}
+	if _, err := db.InsertStruct(ctx, "users", user); err != nil {
+		t.Fatalf("InsertStruct failed: %v", err)
+	}
+	if user.ID != 42 {
+		t.Errorf("ID = %d, want generated key 42", user.ID)
+	}
+
+	withNick := &structUser{Name: "Bob", Nickname: "bobby"}
+	if _, err := db.InsertStruct(ctx, "users", withNick); err != nil || withNick.ID != 43 {
+		t.Errorf("InsertStruct() = %d, %v", withNick.ID, err)
+	}
+
+	explicit := structUser{ID: 7, Name: "Carol"}
+	if _, err := db.InsertStruct(ctx, "users", explicit); err != nil {
+		t.Errorf("InsertStruct with explicit key failed: %v", err)
+	}
+
+	if _, err := db.InsertStruct(ctx, "users", (*structUser)(nil)); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("InsertStruct(nil) error = %v, want ErrInvalidArguments", err)
+	}
+}
+
+func TestInsertManyStructs(t *testing.T) {
+	ctx := context.Background()
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"INSERT INTO `users` (`name`) VALUES (?), (?)":                               {rowsAffected: 2},
+		"INSERT INTO `users` (`id`, `name`, `nickname`) VALUES (?, ?, ?), (?, ?, ?)": {rowsAffected: 2},
+	})
+
+	created := time.Now()
+	users := []structUser{{Name: "Alice", CreatedAt: created}, {Name: "Bob", CreatedAt: created}}
+	if n, err := db.InsertManyStructs(ctx, "users", users); err != nil || n != 2 {
+		t.Errorf("InsertManyStructs() = %d, %v", n, err)
+	}
+
+	explicit := []*structUser{{ID: 1, Name: "Carol", Nickname: "c"}, {ID: 2, Name: "Dave", Nickname: "d"}}
+	if n, err := db.InsertManyStructs(ctx, "users", explicit); err != nil || n != 2 {
+		t.Errorf("InsertManyStructs() with explicit keys = %d, %v", n, err)
+	}
+
+	mixed := []structUser{{Name: "Erin"}, {Name: "Frank", Nickname: "f"}}
+	if _, err := db.InsertManyStructs(ctx, "users", mixed); !errors.Is(err, sqlx.ErrInvalidArguments) || !strings.Contains(err.Error(), "nickname") {
+		t.Errorf("InsertManyStructs() with mixed omitempty error = %v, want ErrInvalidArguments naming the column", err)
+	}
+}
+
+func TestInsertStructReturning(t *testing.T) {
+	db := openFakeSQLX(t, sqlx.PostgreSQL, map[string]fakeResult{
+		`INSERT INTO "users" ("name") VALUES ($1) RETURNING "id"`: {
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(9)}},
+		},
+	})
+
+	user := &structUser{Name: "Dave"}
+	result, err := db.InsertStruct(context.Background(), "users", user)
+	if err != nil {
+		t.Fatalf("InsertStruct failed: %v", err)
+	}
+	if user.ID != 9 {
+		t.Errorf("ID = %d, want returned key 9", user.ID)
+	}
+	if id, err := result.LastInsertId(); err != nil || id != 9 {
+		t.Errorf("LastInsertId() = %d, %v", id, err)
+	}
+}
+
+func TestUpdateAndDeleteStruct(t *testing.T) {
+	ctx := context.Background()
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"UPDATE `users` SET `name` = ? WHERE `id` = ?":                               {rowsAffected: 1},
+		"UPDATE `memberships` SET `kind` = ? WHERE `group_id` = ? AND `user_id` = ?": {rowsAffected: 1},
+		"DELETE FROM `memberships` WHERE `group_id` = ? AND `user_id` = ?":           {rowsAffected: 1},
+	})
+
+	if _, err := db.UpdateStruct(ctx, "users", structUser{ID: 1, Name: "Alice"}); err != nil {
+		t.Errorf("UpdateStruct failed: %v", err)
+	}
+
+	membership := structMembership{UserID: 1, GroupID: 2, Kind: "owner"}
+	result, err := db.UpdateStruct(ctx, "memberships", &membership)
+	if err != nil {
+		t.Fatalf("UpdateStruct with composite key failed: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 1 {
+		t.Errorf("RowsAffected() = %d, want 1", n)
+	}
+
+	if _, err := db.DeleteStruct(ctx, "memberships", membership); err != nil {
+		t.Errorf("DeleteStruct failed: %v", err)
+	}
+
+	if _, err := db.DeleteStruct(ctx, "users", struct {
+		Name string `db:"name"`
+	}{"x"}); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("DeleteStruct without pk error = %v, want ErrInvalidArguments", err)
+	}
+
+	if _, err := db.UpdateStruct(ctx, "users", structUser{Name: "Bob"}); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("UpdateStruct with zero pk error = %v, want ErrInvalidArguments", err)
+	}
+	if _, err := db.DeleteStruct(ctx, "memberships", structMembership{UserID: 1}); !errors.Is(err, sqlx.ErrInvalidArguments) {
+		t.Errorf("DeleteStruct with zero pk error = %v, want ErrInvalidArguments", err)
+	}
+}
+
+// failingJSON cannot be marshaled.
+type failingJSON struct{}
+
+func (failingJSON) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("not encodable")
+}
+
+type structDocument struct {
+	ID       int64          `db:"id,pk"`
+	Settings map[string]any `db:"settings,json"`
+	Tags     []string       `db:"tags,json"`
+	Broken   *failingJSON   `db:"broken,json,omitempty"`
+}
+
+func TestStructWritesMarshalJSON(t *testing.T) {
+	ctx := context.Background()
+	db := openFakeSQLX(t, sqlx.MySQL, map[string]fakeResult{
+		"INSERT INTO `documents` (`id`, `settings`, `tags`) VALUES (?, ?, ?)": {rowsAffected: 1},
+	})
+
+	doc := structDocument{ID: 1, Settings: map[string]any{"theme": "dark"}}
+	if _, err := db.InsertStruct(ctx, "documents", doc); err != nil {
+		t.Fatalf("InsertStruct failed: %v", err)
+	}
+	if n, err := db.InsertManyStructs(ctx, "documents", []structDocument{doc}); err != nil || n != 1 {
+		t.Fatalf("InsertManyStructs() = %d, %v", n, err)
+	}
+
+	doc.Broken = &failingJSON{}
+	_, err := db.UpdateStruct(ctx, "documents", doc)
+	if !errors.Is(err, sqlx.ErrInvalidDataType) || !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), "failingJSON") {
+		t.Errorf("UpdateStruct error = %v, want ErrInvalidDataType naming the column and type", err)
+	}
+}
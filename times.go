@@ -0,0 +1,135 @@
+package sqlx
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts tried for textual times when
+// TimeSettings.Layouts is empty: RFC 3339 and the DATETIME, TIMESTAMP and
+// DATE text of MySQL, PostgreSQL, SQLite and SQL Server. Fractional seconds
+// are optional in every layout.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// TimeSettings controls how times are read from and written to the database.
+// The zero value accepts DefaultTimeLayouts in UTC and leaves written times
+// unchanged.
+type TimeSettings struct {
+	// Layouts are the layouts tried, in order, for time.Time fields read
+	// from text, as MySQL returns without parseTime=true and SQLite returns
+	// for TEXT columns.
+	Layouts []string
+
+	// Location is the location of textual times without a zone offset.
+	// Nil means UTC.
+	Location *time.Location
+
+	// UTC converts time.Time query arguments to UTC before they are written.
+	// It applies to queries run by DB and Tx.
+	UTC bool
+
+	// UnixEpoch reads integer and floating-point values into time.Time
+	// fields as seconds since the Unix epoch, as SQLite stores times with
+	// unixepoch() or strftime('%s').
+	UnixEpoch bool
+}
+
+// location returns Location, defaulting to UTC.
+func (t TimeSettings) location() *time.Location {
+	if t.Location == nil {
+		return time.UTC
+	}
+	return t.Location
+}
+
+// parse converts a database value to a time.Time.
+func (t TimeSettings) parse(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v, nil
+	case []byte:
+		return t.parseText(string(v))
+	case string:
+		return t.parseText(v)
+	}
+
+	if t.UnixEpoch {
+		srcVal := reflect.ValueOf(src)
+		switch srcVal.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			seconds, err := toInt64(srcVal)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w: cannot convert %v to time: %v", ErrInvalidDataType, src, err)
+			}
+			return time.Unix(seconds, 0).In(t.location()), nil
+		case reflect.Float32, reflect.Float64:
+			seconds, frac := math.Modf(srcVal.Float())
+			return time.Unix(int64(seconds), int64(frac*1e9)).In(t.location()), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: cannot convert %T to time.Time", ErrInvalidDataType, src)
+}
+
+// parseText parses a textual time with the first matching layout. Times
+// without a zone offset are in Location.
+func (t TimeSettings) parseText(text string) (time.Time, error) {
+	layouts := t.Layouts
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+
+	text = strings.TrimSpace(text)
+	for _, layout := range layouts {
+		if parsed, err := time.ParseInLocation(layout, text, t.location()); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: cannot parse %q as a time", ErrInvalidDataType, text)
+}
+
+// writeArgs returns args with time.Time values converted to UTC if UTC is
+// set. args itself is not modified.
+func (t TimeSettings) writeArgs(args []any) []any {
+	if !t.UTC {
+		return args
+	}
+
+	var converted []any
+	for i, arg := range args {
+		var utc time.Time
+		switch v := arg.(type) {
+		case time.Time:
+			utc = v.UTC()
+		case *time.Time:
+			if v == nil {
+				continue
+			}
+			utc = v.UTC()
+		default:
+			continue
+		}
+
+		if converted == nil {
+			converted = append([]any(nil), args...)
+		}
+		converted[i] = utc
+	}
+
+	if converted == nil {
+		return args
+	}
+	return converted
+}
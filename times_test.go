@@ -0,0 +1,101 @@
+package sqlx_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dongrv/sqlx"
+)
+
+type timeRecord struct {
+	At      time.Time  `db:"at"`
+	Updated *time.Time `db:"updated"`
+}
+
+func TestBinderTimeLayouts(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	tests := []struct {
+		name     string
+		settings sqlx.TimeSettings
+		value    any
+		want     time.Time
+	}{
+		{"rfc3339", sqlx.TimeSettings{}, "2024-01-02T03:04:05+02:00", time.Date(2024, 1, 2, 1, 4, 5, 0, time.UTC)},
+		{"datetime", sqlx.TimeSettings{}, []byte("2024-01-02 03:04:05"), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"fractional", sqlx.TimeSettings{}, "2024-01-02 03:04:05.123456", time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)},
+		{"date only", sqlx.TimeSettings{}, []byte("2024-01-02"), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"postgres offset", sqlx.TimeSettings{}, "2024-01-02 03:04:05.5+01", time.Date(2024, 1, 2, 2, 4, 5, 500000000, time.UTC)},
+		{"location", sqlx.TimeSettings{Location: berlin}, "2024-01-02 03:04:05", time.Date(2024, 1, 2, 2, 4, 5, 0, time.UTC)},
+		{"custom layout", sqlx.TimeSettings{Layouts: []string{"02/01/2006"}}, "31/12/2023", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"unix seconds", sqlx.TimeSettings{UnixEpoch: true}, int64(1704164645), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"unix float", sqlx.TimeSettings{UnixEpoch: true}, 1704164645.25, time.Date(2024, 1, 2, 3, 4, 5, 250000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := &namedRows{fakeRows{columns: []string{"at", "updated"}, data: [][]any{{tt.value, tt.value}}}}
+			rows.Next()
+
+			var dest timeRecord
+			if err := sqlx.NewBinder().WithTimeSettings(tt.settings).BindRow(rows, &dest); err != nil {
+				t.Fatalf("BindRow failed: %v", err)
+			}
+			if !dest.At.Equal(tt.want) {
+				t.Errorf("At = %v, want %v", dest.At, tt.want)
+			}
+			if dest.Updated == nil || !dest.Updated.Equal(tt.want) {
+				t.Errorf("Updated = %v, want %v", dest.Updated, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinderTimeErrors(t *testing.T) {
+	for _, value := range []any{"yesterday", int64(1704164645)} {
+		rows := &namedRows{fakeRows{columns: []string{"at"}, data: [][]any{{value}}}}
+		rows.Next()
+
+		var dest timeRecord
+		if err := sqlx.NewBinder().BindRow(rows, &dest); !errors.Is(err, sqlx.ErrInvalidDataType) {
+			t.Errorf("BindRow(%v) error = %v, want ErrInvalidDataType", value, err)
+		}
+	}
+}
+
+func TestDBTimeSettings(t *testing.T) {
+	ctx := context.Background()
+	settings := sqlx.TimeSettings{Location: time.FixedZone("EST", -5*3600), UTC: true}
+	db := openFakeSQLX(t, sqlx.SQLite, map[string]fakeResult{
+		"SELECT now":                       {columns: []string{"at"}, rows: [][]driver.Value{{[]byte("2024-01-02 03:04:05")}}},
+		"UPDATE jobs SET run_at = ?":       {rowsAffected: 1},
+		"SELECT id FROM jobs WHERE at > ?": {columns: []string{"id"}},
+	}, func(config sqlx.Config) sqlx.Config {
+		return config.WithTimeSettings(settings)
+	})
+
+	at, err := sqlx.Scalar[time.Time](ctx, db, "SELECT now")
+	if err != nil || !at.Equal(time.Date(2024, 1, 2, 8, 4, 5, 0, time.UTC)) {
+		t.Errorf("Scalar() = %v, %v", at, err)
+	}
+
+	local := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	if _, err := db.Exec(ctx, "UPDATE jobs SET run_at = ?", local); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if args := fakeLastArgs(); len(args) != 1 || args[0].(time.Time).Location() != time.UTC || !args[0].(time.Time).Equal(local) {
+		t.Errorf("Exec args = %v, want the time in UTC", args)
+	}
+
+	if _, err := sqlx.All[timeRecord](ctx, db, "SELECT id FROM jobs WHERE at > ?", &local); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if args := fakeLastArgs(); len(args) != 1 || args[0].(time.Time).Location() != time.UTC {
+		t.Errorf("Query args = %v, want the time in UTC", args)
+	}
+	if local.Location() == time.UTC {
+		t.Error("argument was modified in place")
+	}
+}
@@ -0,0 +1,198 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tx is a transaction started by DB.Begin or DB.InTx. It offers the CRUD
+// helpers of DB bound to the transaction, including locking selects:
+//
+//	err := db.InTx(ctx, func(tx *sqlx.Tx) error {
+//		rows, err := tx.Select(ctx, "jobs", nil, sqlx.Col("state").Eq("queued"),
+//			sqlx.WithLock(sqlx.ForUpdate().SkipLocked()))
+//		...
+//	}, nil)
+type Tx struct {
+	tx     *sql.Tx
+	driver Driver
+	times  TimeSettings
+	hooks  queryHooks
+	cancel context.CancelFunc
+}
+
+// Begin starts a transaction bounded by Config.TransactionTimeout. The
+// caller must end it with Commit or Rollback.
+func (db *DB) Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if db.db == nil {
+		return nil, ErrConnectionClosed
+	}
+
+	ctx, cancel := db.withTimeout(ctx, db.config.TransactionTimeout)
+
+	tx, err := db.db.BeginTx(ctx, opts)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	return &Tx{
+		tx:     tx,
+		driver: db.config.Driver,
+		times:  db.config.Time,
+		hooks:  db.hooks(),
+		cancel: cancel,
+	}, nil
+}
+
+// InTx runs fn in a transaction, committing if fn returns nil and rolling
+// back otherwise.
+func (db *DB) InTx(ctx context.Context, fn func(*Tx) error, opts *sql.TxOptions) error {
+	tx, err := db.Begin(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return endTx(tx, fn(tx))
+}
+
+// endTx commits tx if err is nil and rolls it back otherwise.
+func endTx(tx interface {
+	Commit() error
+	Rollback() error
+}, err error) error {
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return fmt.Errorf("%w: %v (rollback error: %v)", ErrTransactionFailed, err, rbErr)
+		}
+		return fmt.Errorf("%w: %w", ErrTransactionFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Commit commits the transaction.
+func (tx *Tx) Commit() error {
+	defer tx.cancel()
+	return tx.tx.Commit()
+}
+
+// Rollback aborts the transaction.
+func (tx *Tx) Rollback() error {
+	defer tx.cancel()
+	return tx.tx.Rollback()
+}
+
+// Exec executes a query without returning any rows.
+func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if query == "" {
+		return nil, ErrInvalidQuery
+	}
+	args = tx.times.writeArgs(args)
+	start := time.Now()
+	result, err := tx.tx.ExecContext(ctx, query, args...)
+	if err = tx.hooks.finish(ctx, query, args, start, err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Query executes a query that returns rows.
+func (tx *Tx) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if query == "" {
+		return nil, ErrInvalidQuery
+	}
+	args = tx.times.writeArgs(args)
+	start := time.Now()
+	rows, err := tx.tx.QueryContext(ctx, query, args...)
+	if err = tx.hooks.finish(ctx, query, args, start, err); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// QueryRow executes a query that is expected to return at most one row.
+func (tx *Tx) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	args = tx.times.writeArgs(args)
+	start := time.Now()
+	row := tx.tx.QueryRowContext(ctx, query, args...)
+	tx.hooks.finish(ctx, query, args, start, row.Err())
+	return row
+}
+
+// Insert inserts a row into the specified table.
+func (tx *Tx) Insert(ctx context.Context, table string, data map[string]any) (sql.Result, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no data to insert", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildInsertQueryWithDriver(tx.driver, table, data)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(ctx, query, args...)
+}
+
+// Update updates rows in the specified table. See DB.Update.
+func (tx *Tx) Update(ctx context.Context, table string, data map[string]any, where Condition) (sql.Result, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no data to update", ErrInvalidArguments)
+	}
+
+	query, args, err := BuildUpdateQueryWithDriver(tx.driver, table, data, where)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(ctx, query, args...)
+}
+
+// Delete deletes rows from the specified table. See DB.Delete.
+func (tx *Tx) Delete(ctx context.Context, table string, where Condition) (sql.Result, error) {
+	query, args, err := BuildDeleteQueryWithDriver(tx.driver, table, where)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(ctx, query, args...)
+}
+
+// Select selects rows from the specified table. Unlike DB.Select, it
+// accepts WithLock.
+func (tx *Tx) Select(ctx context.Context, table string, columns []string, where Condition, opts ...SelectOption) (*sql.Rows, error) {
+	query, args, err := buildSelectWithDriver(tx.driver, table, columns, where, newSelectOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return tx.Query(ctx, query, args...)
+}
+
+// SelectOne selects a single row from the specified table. Unlike
+// DB.SelectOne, it accepts WithLock; a lock the dialect does not support is
+// returned as an error.
+func (tx *Tx) SelectOne(ctx context.Context, table string, columns []string, where Condition, opts ...SelectOption) (*sql.Row, error) {
+	query, args, err := buildSelectWithDriver(tx.driver, table, columns, where, newSelectOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return tx.QueryRow(ctx, query, args...), nil
+}
+
+// Rebind converts a query written with '?' placeholders to the driver's style.
+func (tx *Tx) Rebind(query string) string {
+	return Rebind(tx.driver, query)
+}
+
+// RawTx returns the underlying *sql.Tx.
+func (tx *Tx) RawTx() *sql.Tx {
+	return tx.tx
+}
+
+// binder returns a default Binder with the configured time settings.
+func (tx *Tx) binder() *Binder {
+	return NewBinder().WithTimeSettings(tx.times)
+}
@@ -1,14 +1,22 @@
 package sqlx
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 )
 
 // RowScanner defines the interface for scanning a single row.
@@ -24,6 +32,13 @@ type RowsScanner interface {
 	Close() error
 }
 
+// ColumnsScanner is implemented by result sets that report their column
+// names, such as *sql.Rows. Binder maps result columns to struct fields by
+// name when the scanner implements it, and positionally otherwise.
+type ColumnsScanner interface {
+	Columns() ([]string, error)
+}
+
 // ColumnList is a slice of column names with helper methods.
 type ColumnList []string
 
@@ -259,6 +274,13 @@ type Binder struct {
 
 	// UseFieldNames specifies whether to use field names when tag is not present.
 	UseFieldNames bool
+
+	// StrictColumns makes binding fail on result columns that match no
+	// field instead of discarding them.
+	StrictColumns bool
+
+	// Time controls how time.Time fields are read from text and numbers.
+	Time TimeSettings
 }
 
 // NewBinder creates a new Binder with default settings.
@@ -281,7 +303,21 @@ func (b *Binder) WithUseFieldNames(use bool) *Binder {
 	return b
 }
 
+// WithStrictColumns sets whether unknown result columns are an error and returns the Binder for chaining.
+func (b *Binder) WithStrictColumns(strict bool) *Binder {
+	b.StrictColumns = strict
+	return b
+}
+
+// WithTimeSettings sets the time settings and returns the Binder for chaining.
+func (b *Binder) WithTimeSettings(settings TimeSettings) *Binder {
+	b.Time = settings
+	return b
+}
+
 // BindRow binds a single row to a struct.
+// If rows implements ColumnsScanner, as *sql.Rows does, columns are matched
+// to fields by name; otherwise they are scanned in field order.
 func (b *Binder) BindRow(rows RowScanner, dest any) error {
 	destVal := reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Ptr || destVal.IsNil() {
@@ -298,19 +334,17 @@ func (b *Binder) BindRow(rows RowScanner, dest any) error {
 		return err
 	}
 
-	values := make([]any, len(columns))
-	for i := range values {
-		values[i] = new(any)
-	}
-
-	if err := rows.Scan(values...); err != nil {
+	plan, err := b.planColumns(rows, columns)
+	if err != nil {
 		return err
 	}
+	plan.times = b.Time
 
-	return b.setValues(elem, columns, values)
+	return plan.bind(rows, elem)
 }
 
 // BindRows binds multiple rows to a slice of structs.
+// Columns are matched to fields as in BindRow.
 func (b *Binder) BindRows(rows RowsScanner, dest any) error {
 	destVal := reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Ptr || destVal.IsNil() {
@@ -341,360 +375,1203 @@ func (b *Binder) BindRows(rows RowsScanner, dest any) error {
 		return err
 	}
 
-	// Process rows
+	plan, err := b.planColumns(rows, columns)
+	if err != nil {
+		return err
+	}
+	plan.times = b.Time
+
+	// Process rows, binding each one in place in the slice.
 	for rows.Next() {
-		values := make([]any, len(columns))
-		for i := range values {
-			values[i] = new(any)
-		}
+		n := sliceVal.Len()
+		sliceVal.Set(reflect.Append(sliceVal, reflect.Zero(elemType)))
 
-		if err := rows.Scan(values...); err != nil {
-			return err
+		newElem := sliceVal.Index(n)
+		if elemType.Kind() == reflect.Ptr {
+			newElem.Set(reflect.New(elemType.Elem()))
+			newElem = newElem.Elem()
 		}
 
-		// Create new element
-		var newElem reflect.Value
-		if elemType.Kind() == reflect.Ptr {
-			newElem = reflect.New(elemType.Elem())
-			if err := b.setValues(newElem.Elem(), columns, values); err != nil {
-				return err
-			}
-			sliceVal.Set(reflect.Append(sliceVal, newElem))
-		} else {
-			newElem = reflect.New(elemType).Elem()
-			if err := b.setValues(newElem, columns, values); err != nil {
-				return err
-			}
-			sliceVal.Set(reflect.Append(sliceVal, newElem))
+		if err := plan.bind(rows, newElem); err != nil {
+			sliceVal.SetLen(n)
+			return err
 		}
 	}
 
 	return rows.Err()
 }
 
-// getColumns extracts column information from a struct.
-func (b *Binder) getColumns(structVal reflect.Value) ([]structField, error) {
-	structType := structVal.Type()
-	fields := make([]structField, 0, structType.NumField())
+// columnPlan maps the columns of a result set to struct fields.
+type columnPlan struct {
+	// fields are the matched fields in column order.
+	fields []structField
 
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
+	// positions are the result column positions of fields.
+	positions []int
 
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
+	// direct marks fields whose type is the column's scan type; they are
+	// scanned straight into the field instead of through an any value.
+	direct []bool
 
-		// Get tag value
-		tag := field.Tag.Get(b.TagName)
-		if tag == "-" {
-			continue
+	// targets are the Scan destinations, reused for every row. Columns
+	// that are not scanned directly use the *any holders in raw.
+	targets []any
+	raw     []any
+
+	// times are the time settings of the Binder.
+	times TimeSettings
+}
+
+// columnTypesScanner is implemented by *sql.Rows.
+type columnTypesScanner interface {
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+// newColumnPlan returns a plan for a result set of width columns.
+func newColumnPlan(width int) *columnPlan {
+	plan := &columnPlan{targets: make([]any, width), raw: make([]any, width)}
+	for i := range plan.raw {
+		plan.raw[i] = new(any)
+	}
+	return plan
+}
+
+// planColumns matches the result columns of rows to fields by name, first
+// exactly and then case-insensitively. Scanners that do not report their
+// columns are scanned positionally into fields.
+func (b *Binder) planColumns(rows RowScanner, fields []structField) (*columnPlan, error) {
+	scanner, ok := rows.(ColumnsScanner)
+	if !ok {
+		plan := newColumnPlan(len(fields))
+		plan.fields = fields
+		plan.positions = make([]int, len(fields))
+		plan.direct = make([]bool, len(fields))
+		for i := range plan.positions {
+			plan.positions[i] = i
 		}
+		return plan, nil
+	}
 
-		columnName := tag
-		if columnName == "" && b.UseFieldNames {
-			columnName = field.Name
+	columns, err := scanner.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	exact := make(map[string]int, 2*len(fields))
+	folded := make(map[string]int, 2*len(fields))
+	for i, field := range fields {
+		for _, name := range []string{field.Name, field.Path} {
+			if _, ok := exact[name]; !ok {
+				exact[name] = i
+			}
+			if _, ok := folded[strings.ToLower(name)]; !ok {
+				folded[strings.ToLower(name)] = i
+			}
 		}
+	}
 
-		if columnName != "" {
-			fields = append(fields, structField{
-				Index:      i,
-				Name:       columnName,
-				FieldType:  field.Type,
-				FieldValue: structVal.Field(i),
-			})
+	plan := newColumnPlan(len(columns))
+	for pos, column := range columns {
+		i, ok := exact[column]
+		if !ok {
+			i, ok = folded[strings.ToLower(column)]
+		}
+		if !ok {
+			if b.StrictColumns {
+				return nil, fmt.Errorf("%w: column %q has no matching struct field", ErrInvalidColumnName, column)
+			}
+			continue
+		}
+		plan.fields = append(plan.fields, fields[i])
+		plan.positions = append(plan.positions, pos)
+	}
+
+	plan.direct = make([]bool, len(plan.fields))
+	if typed, ok := rows.(columnTypesScanner); ok {
+		types, err := typed.ColumnTypes()
+		if err == nil && len(types) == len(columns) {
+			for i, field := range plan.fields {
+				// A pointer on the path would have to be allocated
+				// before the value is known to be non-NULL.
+				plan.direct[i] = !field.ViaPointer && !field.JSON && directScan(types[plan.positions[i]], field.FieldType)
+			}
 		}
 	}
 
-	if len(fields) == 0 {
-		return nil, fmt.Errorf("no fields found with tag %q", b.TagName)
+	return plan, nil
+}
+
+// directScan reports whether database/sql can scan a column straight into a
+// field of fieldType with the Binder's semantics: the column's scan type is
+// the field type, or text for a string field, and NULL cannot reach a field
+// that would reject it. Many drivers, such as lib/pq and pgx, report int64 or
+// string scan types for nullable columns, so the column must be reported NOT
+// NULL unless the field itself accepts NULL, like sql.NullString or any.
+func directScan(column *sql.ColumnType, fieldType reflect.Type) bool {
+	scanType := column.ScanType()
+	if scanType == nil {
+		return false
 	}
 
-	return fields, nil
+	acceptsNull := fieldType.Kind() == reflect.Interface || reflect.PointerTo(fieldType).Implements(scannerType)
+	if !acceptsNull {
+		if nullable, ok := column.Nullable(); !ok || nullable {
+			return false
+		}
+	}
+
+	if scanType == fieldType {
+		return true
+	}
+	return fieldType.Kind() == reflect.String && !acceptsNull &&
+		scanType.Kind() == reflect.Slice && scanType.Elem().Kind() == reflect.Uint8
 }
 
-// setValues sets values from the database into struct fields.
-func (b *Binder) setValues(structVal reflect.Value, fields []structField, values []any) error {
-	for i, field := range fields {
-		rawValue := *(values[i].(*any))
-		if rawValue == nil {
-			continue
+// bind scans the current row into the matched fields of structVal.
+// Unmatched columns are scanned and discarded.
+func (p *columnPlan) bind(rows RowScanner, structVal reflect.Value) error {
+	copy(p.targets, p.raw)
+	for i, pos := range p.positions {
+		if p.direct[i] {
+			if fieldVal := structVal.FieldByIndex(p.fields[i].Index); fieldVal.CanSet() {
+				p.targets[pos] = fieldVal.Addr().Interface()
+			}
 		}
+	}
+
+	if err := rows.Scan(p.targets...); err != nil {
+		return err
+	}
 
-		fieldVal := structVal.Field(field.Index)
-		if !fieldVal.CanSet() {
+	for i, pos := range p.positions {
+		if p.targets[pos] != p.raw[pos] {
 			continue
 		}
-
-		// Convert the value to the field type
-		converted, err := convertValue(rawValue, field.FieldType)
-		if err != nil {
-			return fmt.Errorf("field %s: %w", field.Name, err)
+		if err := setField(structVal, &p.fields[i], *(p.raw[pos].(*any)), p.times); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// getColumns extracts column information from a struct.
+//
+// Anonymous embedded structs are flattened into the outer struct. Other
+// struct fields are nested under their tag or field name, so the City field
+// of a field `Addr Address` tagged db:"addr" maps to the column "addr_city",
+// also matched as "addr.city" when binding. Pointers to structs on the way are allocated
+// when a non-NULL value is bound. Structs that scan or bind as a single
+// value, such as time.Time and sql.NullString, are not nested.
+//
+// A field tagged with the json option, as in `db:"meta,json"`, maps to a
+// single column holding JSON text, which is unmarshaled into the field.
+//
+// When several fields map to the same column, the shallowest one wins,
+// then a tagged one, as in encoding/json; if that leaves a tie, the column
+// is dropped.
+//
+// The fields are computed once per struct type and tag settings and shared
+// by all Binders; callers must not modify them.
+func (b *Binder) getColumns(structVal reflect.Value) ([]structField, error) {
+	key := fieldCacheKey{structType: structVal.Type(), tagName: b.TagName, useFieldNames: b.UseFieldNames}
 
-		fieldVal.Set(converted)
+	cached, ok := fieldCache.Load(key)
+	if !ok {
+		cached, _ = fieldCache.LoadOrStore(key, b.structFields(key.structType))
 	}
 
-	return nil
+	fields := cached.([]structField)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields found with tag %q", b.TagName)
+	}
+	return fields, nil
 }
 
-// structField represents a struct field with its database mapping.
-type structField struct {
-	Index      int
-	Name       string
-	FieldType  reflect.Type
-	FieldValue reflect.Value
+// fieldCacheKey identifies the fields of a struct type under a tag setting.
+type fieldCacheKey struct {
+	structType    reflect.Type
+	tagName       string
+	useFieldNames bool
 }
 
-// convertValue converts a value from the database to the target type.
-func convertValue(src any, targetType reflect.Type) (reflect.Value, error) {
-	srcVal := reflect.ValueOf(src)
-	srcType := srcVal.Type()
+// fieldCache maps a fieldCacheKey to its []structField.
+var fieldCache sync.Map
 
-	// If types match, return directly
-	if srcType.AssignableTo(targetType) {
-		return srcVal, nil
-	}
+// structFields computes the column fields of a struct type for getColumns.
+func (b *Binder) structFields(structType reflect.Type) []structField {
+	var candidates []structField
+	b.walkFields(structType, nil, "", false, map[reflect.Type]bool{}, &candidates)
 
-	// Handle nil values
-	if src == nil {
-		return reflect.Zero(targetType), nil
+	sort.SliceStable(candidates, func(i, j int) bool {
+		x, y := candidates[i], candidates[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		if len(x.Index) != len(y.Index) {
+			return len(x.Index) < len(y.Index)
+		}
+		return x.Tagged && !y.Tagged
+	})
+
+	fields := make([]structField, 0, len(candidates))
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].Name == candidates[i].Name {
+			j++
+		}
+		if dominant, ok := dominantField(candidates[i:j]); ok {
+			fields = append(fields, dominant)
+		}
+		i = j
 	}
 
-	// Handle common conversions
-	switch targetType.Kind() {
-	case reflect.String:
-		return reflect.ValueOf(fmt.Sprint(src)), nil
+	// Restore declaration order.
+	sort.Slice(fields, func(i, j int) bool {
+		return slices.Compare(fields[i].Index, fields[j].Index) < 0
+	})
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		switch v := src.(type) {
-		case int64:
-			return reflect.ValueOf(v).Convert(targetType), nil
-		case int32:
-			return reflect.ValueOf(int64(v)).Convert(targetType), nil
-		case int:
-			return reflect.ValueOf(int64(v)).Convert(targetType), nil
-		case float64:
-			return reflect.ValueOf(int64(v)).Convert(targetType), nil
-		default:
-			return reflect.Value{}, fmt.Errorf("cannot convert %T to %v", src, targetType)
+	return fields
+}
+
+// walkFields appends the column fields of structType to fields, recursing
+// into embedded and nested structs. viaPointer reports whether structType
+// was reached through a struct pointer; visiting guards against recursive types.
+func (b *Binder) walkFields(structType reflect.Type, index []int, prefix string, viaPointer bool, visiting map[reflect.Type]bool, fields *[]structField) {
+	visiting[structType] = true
+	defer delete(visiting, structType)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		// Get tag value
+		tag := field.Tag.Get(b.TagName)
+		if tag == "-" {
+			continue
 		}
+		name, options, _ := strings.Cut(tag, ",")
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		switch v := src.(type) {
-		case int64:
-			return reflect.ValueOf(uint64(v)).Convert(targetType), nil
-		case uint64:
-			return reflect.ValueOf(v).Convert(targetType), nil
-		default:
-			return reflect.Value{}, fmt.Errorf("cannot convert %T to %v", src, targetType)
+		var sf structField
+		sf.parseOptions(options)
+
+		// JSON fields hold a single column whatever their type.
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
 		}
+		nested := fieldType.Kind() == reflect.Struct && !isValueStruct(fieldType) && !sf.JSON
 
-	case reflect.Float32, reflect.Float64:
-		switch v := src.(type) {
-		case float64:
-			return reflect.ValueOf(v).Convert(targetType), nil
-		case int64:
-			return reflect.ValueOf(float64(v)).Convert(targetType), nil
-		default:
-			return reflect.Value{}, fmt.Errorf("cannot convert %T to %v", src, targetType)
+		// Skip unexported fields, except embedded structs whose exported
+		// fields are promoted. Unexported embedded pointers cannot be allocated.
+		if !field.IsExported() && !(field.Anonymous && nested && field.Type.Kind() != reflect.Ptr) {
+			continue
 		}
 
-	case reflect.Bool:
-		switch v := src.(type) {
-		case bool:
-			return reflect.ValueOf(v), nil
-		case int64:
-			return reflect.ValueOf(v != 0), nil
-		default:
-			return reflect.Value{}, fmt.Errorf("cannot convert %T to bool", src)
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if nested && visiting[fieldType] {
+			continue
 		}
 
-	case reflect.Struct:
-		// Handle time.Time
-		if targetType == reflect.TypeOf(time.Time{}) {
-			switch v := src.(type) {
-			case time.Time:
-				return reflect.ValueOf(v), nil
-			case []byte:
-				t, err := time.Parse(time.RFC3339, string(v))
-				if err != nil {
-					return reflect.Value{}, fmt.Errorf("cannot parse time: %w", err)
-				}
-				return reflect.ValueOf(t), nil
-			case string:
-				t, err := time.Parse(time.RFC3339, v)
-				if err != nil {
-					return reflect.Value{}, fmt.Errorf("cannot parse time: %w", err)
-				}
-				return reflect.ValueOf(t), nil
+		fieldViaPointer := viaPointer || (nested && field.Type.Kind() == reflect.Ptr)
+
+		if field.Anonymous && nested && name == "" {
+			b.walkFields(fieldType, fieldIndex, prefix, fieldViaPointer, visiting, fields)
+			continue
+		}
+
+		// A tag with only options, such as ",pk", still selects the field.
+		tagged := name != ""
+		if !tagged {
+			if tag == "" && !b.UseFieldNames {
+				continue
 			}
+			name = field.Name
+		}
+
+		if nested {
+			b.walkFields(fieldType, fieldIndex, prefix+name+".", fieldViaPointer, visiting, fields)
+			continue
 		}
-	}
 
-	return reflect.Value{}, fmt.Errorf("cannot convert %T to %v", src, targetType)
+		path := prefix + name
+		sf.Index = fieldIndex
+		sf.Name = strings.ReplaceAll(path, ".", "_")
+		sf.Path = path
+		sf.FieldType = field.Type
+		sf.Tagged = tagged
+		sf.ViaPointer = viaPointer
+		sf.Time = fieldType == timeType && !sf.JSON
+		sf.Convert = converterFor(field.Type)
+		if sf.JSON {
+			sf.Convert = unmarshalJSON
+		}
+		*fields = append(*fields, sf)
+	}
 }
 
-// ContextWithTimeout creates a context with timeout from options.
-func ContextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	if timeout > 0 {
-		return context.WithTimeout(ctx, timeout)
+// dominantField returns the field that wins among fields sharing a column
+// name, sorted by depth and then tagged first.
+func dominantField(fields []structField) (structField, bool) {
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) && fields[0].Tagged == fields[1].Tagged {
+		return structField{}, false
 	}
-	return ctx, func() {}
+	return fields[0], true
 }
 
-// IsContextError checks if an error is a context error (deadline or cancellation).
-func IsContextError(err error) bool {
-	if err == nil {
-		return false
+// isValueStruct reports whether a struct type is bound as a single value
+// rather than nested: time.Time, sql.Scanner and driver.Valuer types.
+func isValueStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(scannerType) || t.Implements(valuerType)
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// fieldByIndex returns the field at index, allocating nil struct pointers on
+// the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
 	}
-	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+	return v
 }
 
-// ShouldRetry checks if an operation should be retried based on the error.
-func ShouldRetry(err error) bool {
-	if err == nil {
-		return false
+// fieldByIndexRead returns the field at index, or false if a struct pointer
+// on the way is nil.
+func fieldByIndexRead(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
 	}
+	return v, true
+}
 
-	// Retry on context errors (except cancellation)
-	if errors.Is(err, context.DeadlineExceeded) {
-		return true
+// setField sets a value from the database into a struct field.
+// Fields whose address implements sql.Scanner scan the raw value, pointer
+// fields are allocated for values and set to nil for NULL. Time fields
+// read text and numbers with the time settings.
+func setField(structVal reflect.Value, field *structField, rawValue any, times TimeSettings) error {
+	if rawValue == nil {
+		// NULL resets the field to nil or its zero value, such as an
+		// invalid sql.NullString, without allocating parent pointers.
+		if fieldVal, ok := fieldByIndexRead(structVal, field.Index); ok && fieldVal.CanSet() {
+			fieldVal.Set(reflect.Zero(field.FieldType))
+		}
+		return nil
 	}
 
-	// Retry on connection errors
-	errStr := strings.ToLower(err.Error())
-	retryKeywords := []string{
-		"connection",
-		"timeout",
-		"deadline",
-		"busy",
-		"locked",
-		"temporary",
-		"retry",
+	fieldVal := fieldByIndex(structVal, field.Index)
+	if !fieldVal.CanSet() {
+		return nil
 	}
 
-	for _, keyword := range retryKeywords {
-		if strings.Contains(errStr, keyword) {
-			return true
+	if field.Time {
+		t, err := times.parse(rawValue)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
 		}
+		rawValue = t
 	}
 
-	return false
+	// Convert the value to the field type
+	if err := field.Convert(fieldVal, rawValue); err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	return nil
 }
 
-// BuildInsertQuery builds an INSERT query string.
-func BuildInsertQuery(table string, data map[string]any) (string, []any) {
-	columns := make([]string, 0, len(data))
-	placeholders := make([]string, 0, len(data))
-	args := make([]any, 0, len(data))
-
-	for column, value := range data {
-		columns = append(columns, escapeIdentifier(column))
-		placeholders = append(placeholders, "?")
-		args = append(args, value)
+// structToMap converts a struct into a Map keyed by the binder's column names.
+func (b *Binder) structToMap(structVal reflect.Value) (Map, error) {
+	fields, err := b.getColumns(structVal)
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		escapeIdentifier(table),
-		strings.Join(columns, ", "),
-		strings.Join(placeholders, ", "),
-	)
-
-	return query, args
+	m := make(Map, len(fields))
+	for i := range fields {
+		value, _, err := fields[i].value(structVal)
+		if err != nil {
+			return nil, err
+		}
+		m[fields[i].Name] = value
+	}
+	return m, nil
 }
 
-// BuildInsertQueryWithDriver builds an INSERT query string with driver-specific escaping.
-func BuildInsertQueryWithDriver(driver Driver, table string, data map[string]any) (string, []any, error) {
-	escapedTable, err := EscapeTableName(driver, table)
-	if err != nil {
-		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
+// structsToMaps converts a slice of structs or struct pointers into the Maps
+// inserted by InsertManyStructs. Fields are left out as by InsertStruct, but
+// since all rows share one column list, a zero autoincrement or omitempty
+// field must be zero in every row or in none.
+func structsToMaps(binder *Binder, rows any) ([]Map, error) {
+	sliceVal := reflect.ValueOf(rows)
+	if sliceVal.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w: rows must be a slice of structs, got %T", ErrInvalidArguments, rows)
 	}
 
-	columns := make([]string, 0, len(data))
-	placeholders := make([]string, 0, len(data))
-	args := make([]any, 0, len(data))
+	maps := make([]Map, sliceVal.Len())
+	for i := range maps {
+		elem := sliceVal.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return nil, fmt.Errorf("%w: row %d is nil", ErrInvalidArguments, i)
+			}
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%w: row %d is %s, not a struct", ErrInvalidArguments, i, elem.Kind())
+		}
 
-	for column, value := range data {
-		escapedCol, err := EscapeColumnName(driver, column)
+		fields, err := binder.getColumns(elem)
 		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
+			return nil, err
 		}
-		columns = append(columns, escapedCol)
-		placeholders = append(placeholders, "?")
-		args = append(args, value)
+		m, _, err := insertData(elem, fields)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		maps[i] = m
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		escapedTable,
-		strings.Join(columns, ", "),
-		strings.Join(placeholders, ", "),
-	)
-
-	return query, args, nil
+	for i := 1; i < len(maps); i++ {
+		if column, ok := firstMissingColumn(maps[0], maps[i]); ok {
+			return nil, fmt.Errorf("%w: column %q is left out of row 0 but not row %d; autoincrement and omitempty fields must be zero in all rows or none", ErrInvalidArguments, column, i)
+		}
+		if column, ok := firstMissingColumn(maps[i], maps[0]); ok {
+			return nil, fmt.Errorf("%w: column %q is left out of row %d but not row 0; autoincrement and omitempty fields must be zero in all rows or none", ErrInvalidArguments, column, i)
+		}
+	}
+	return maps, nil
 }
 
-// BuildUpdateQuery builds an UPDATE query string.
-func BuildUpdateQuery(table string, data, where map[string]any) (string, []any) {
-	setClauses := make([]string, 0, len(data))
-	args := make([]any, 0, len(data))
+// firstMissingColumn returns the first column, in sorted order, of b that a
+// does not have.
+func firstMissingColumn(a, b Map) (string, bool) {
+	keys := b.Keys()
+	sort.Strings(keys)
+	for _, column := range keys {
+		if _, ok := a[column]; !ok {
+			return column, true
+		}
+	}
+	return "", false
+}
 
-	for column, value := range data {
-		setClauses = append(setClauses, fmt.Sprintf("%s = ?", escapeIdentifier(column)))
-		args = append(args, value)
+// columnNames returns the column names the binder maps for a struct pointer.
+func (b *Binder) columnNames(dest any) ([]string, error) {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() || destVal.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dest must be a non-nil pointer to a struct")
 	}
 
-	whereClauses := make([]string, 0, len(where))
-	for column, value := range where {
-		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", escapeIdentifier(column)))
-		args = append(args, value)
+	fields, err := b.getColumns(destVal.Elem())
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names, nil
+}
+
+// bindMap sets the fields of a struct pointer from a Map keyed by column name.
+// Columns missing from the map leave their fields untouched.
+func (b *Binder) bindMap(m Map, dest any) error {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() || destVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a non-nil pointer to a struct")
+	}
+
+	elem := destVal.Elem()
+	fields, err := b.getColumns(elem)
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		value, ok := m[fields[i].Name]
+		if !ok {
+			value, ok = m[fields[i].Path]
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(elem, &fields[i], value, b.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structField represents a struct field with its database mapping.
+type structField struct {
+	// Index is the index path of the field, as used by reflect.Value.FieldByIndex.
+	Index []int
+
+	// Name is the column name; nested names are joined with "_".
+	Name string
+
+	// Path is the column name with nested names joined with ".".
+	Path string
+
+	// FieldType is the type of the field.
+	FieldType reflect.Type
+
+	// Tagged reports whether the name came from a struct tag.
+	Tagged bool
+
+	// ViaPointer reports whether a struct pointer lies on the path to the field.
+	ViaPointer bool
+
+	// Convert stores a non-NULL database value in the field.
+	Convert func(dst reflect.Value, src any) error
+
+	// PrimaryKey is set by the "pk" tag option.
+	PrimaryKey bool
+
+	// AutoIncrement is set by the "autoincrement" tag option.
+	AutoIncrement bool
+
+	// OmitEmpty is set by the "omitempty" tag option.
+	OmitEmpty bool
+
+	// ReadOnly is set by the "readonly" tag option.
+	ReadOnly bool
+
+	// JSON is set by the "json" tag option: the field is stored as JSON text.
+	JSON bool
+
+	// Time reports whether the field is a time.Time or *time.Time.
+	Time bool
+}
+
+// parseOptions sets the field options from the comma-separated options of a
+// tag such as `db:"id,pk,autoincrement"`. Unknown options are ignored.
+func (f *structField) parseOptions(options string) {
+	for options != "" {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		switch strings.TrimSpace(option) {
+		case "pk":
+			f.PrimaryKey = true
+		case "autoincrement":
+			f.AutoIncrement = true
+		case "omitempty":
+			f.OmitEmpty = true
+		case "readonly":
+			f.ReadOnly = true
+		case "json":
+			f.JSON = true
+		}
+	}
+}
+
+// value returns the value to write for the field of structVal and whether
+// the field is zero. Fields under a nil struct pointer are NULL, and JSON
+// fields are marshaled to a string, or NULL for a nil pointer, map or slice.
+func (f *structField) value(structVal reflect.Value) (any, bool, error) {
+	fieldVal, ok := fieldByIndexRead(structVal, f.Index)
+	if !ok {
+		return nil, true, nil
+	}
+	if !f.JSON {
+		return fieldVal.Interface(), fieldVal.IsZero(), nil
+	}
+
+	switch fieldVal.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if fieldVal.IsNil() {
+			return nil, true, nil
+		}
+	}
+
+	data, err := json.Marshal(fieldVal.Interface())
+	if err != nil {
+		return nil, false, fmt.Errorf("field %s: %w: cannot marshal %v as JSON: %v", f.Name, ErrInvalidDataType, f.FieldType, err)
+	}
+	return string(data), fieldVal.IsZero(), nil
+}
+
+// unmarshalJSON is the Convert function of JSON fields. It decodes JSON
+// text, as []byte or string, into dst.
+func unmarshalJSON(dst reflect.Value, src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("%w: cannot decode %T as JSON into %v", ErrInvalidDataType, src, dst.Type())
+	}
+
+	// Decode into a zero value so that maps are not merged with old contents.
+	dst.Set(reflect.Zero(dst.Type()))
+	if err := json.Unmarshal(data, dst.Addr().Interface()); err != nil {
+		return fmt.Errorf("%w: cannot unmarshal JSON into %v: %v", ErrInvalidDataType, dst.Type(), err)
+	}
+	return nil
+}
+
+// converterFor returns the Convert function for fields of type t: assignValue
+// with fast paths for the values drivers commonly return for basic kinds.
+func converterFor(t reflect.Type) func(reflect.Value, any) error {
+	if t.Kind() == reflect.Ptr || reflect.PointerTo(t).Implements(scannerType) {
+		return assignValue
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(dst reflect.Value, src any) error {
+			switch v := src.(type) {
+			case string:
+				dst.SetString(v)
+			case []byte:
+				dst.SetString(string(v))
+			default:
+				return assignValue(dst, src)
+			}
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(dst reflect.Value, src any) error {
+			if n, ok := src.(int64); ok && !dst.OverflowInt(n) {
+				dst.SetInt(n)
+				return nil
+			}
+			return assignValue(dst, src)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(dst reflect.Value, src any) error {
+			if n, ok := src.(int64); ok && n >= 0 && !dst.OverflowUint(uint64(n)) {
+				dst.SetUint(uint64(n))
+				return nil
+			}
+			return assignValue(dst, src)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		return func(dst reflect.Value, src any) error {
+			if f, ok := src.(float64); ok && !dst.OverflowFloat(f) {
+				dst.SetFloat(f)
+				return nil
+			}
+			return assignValue(dst, src)
+		}
+
+	case reflect.Bool:
+		return func(dst reflect.Value, src any) error {
+			if v, ok := src.(bool); ok {
+				dst.SetBool(v)
+				return nil
+			}
+			return assignValue(dst, src)
+		}
+	}
+
+	return assignValue
+}
+
+// assignValue stores a non-NULL database value in dst. Pointer fields are
+// allocated, types implementing sql.Scanner scan the value themselves and
+// everything else goes through convertValue.
+func assignValue(dst reflect.Value, src any) error {
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	if dst.CanAddr() {
+		if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(src)
+		}
+	}
+
+	converted, err := convertValue(src, dst.Type())
+	if err != nil {
+		return err
+	}
+	dst.Set(converted)
+	return nil
+}
+
+// convertValue converts a value from the database to the target type.
+// Numeric targets accept integers, floats, bools and their textual forms,
+// including the []byte values MySQL returns, and reject values that
+// overflow the target or lose precision.
+func convertValue(src any, targetType reflect.Type) (reflect.Value, error) {
+	// Handle nil values
+	if src == nil {
+		return reflect.Zero(targetType), nil
+	}
+
+	srcVal := reflect.ValueOf(src)
+
+	// If types match, return directly
+	if srcVal.Type().AssignableTo(targetType) {
+		return srcVal, nil
+	}
+
+	result := reflect.New(targetType).Elem()
+
+	// Handle common conversions
+	switch targetType.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case []byte:
+			result.SetString(string(v))
+		case time.Time:
+			result.SetString(v.Format(time.RFC3339Nano))
+		default:
+			result.SetString(fmt.Sprint(src))
+		}
+		return result, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt64(srcVal)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: cannot convert %T to %v: %v", ErrInvalidDataType, src, targetType, err)
+		}
+		if result.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("%w: value %d overflows %v", ErrInvalidDataType, n, targetType)
+		}
+		result.SetInt(n)
+		return result, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := toUint64(srcVal)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: cannot convert %T to %v: %v", ErrInvalidDataType, src, targetType, err)
+		}
+		if result.OverflowUint(n) {
+			return reflect.Value{}, fmt.Errorf("%w: value %d overflows %v", ErrInvalidDataType, n, targetType)
+		}
+		result.SetUint(n)
+		return result, nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(srcVal)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: cannot convert %T to %v: %v", ErrInvalidDataType, src, targetType, err)
+		}
+		if result.OverflowFloat(f) {
+			return reflect.Value{}, fmt.Errorf("%w: value %g overflows %v", ErrInvalidDataType, f, targetType)
+		}
+		result.SetFloat(f)
+		return result, nil
+
+	case reflect.Bool:
+		v, err := toBool(srcVal)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: cannot convert %T to bool: %v", ErrInvalidDataType, src, err)
+		}
+		result.SetBool(v)
+		return result, nil
+
+	case reflect.Slice:
+		if targetType.Elem().Kind() == reflect.Uint8 {
+			switch v := src.(type) {
+			case string:
+				result.SetBytes([]byte(v))
+				return result, nil
+			case []byte:
+				result.SetBytes(bytes.Clone(v))
+				return result, nil
+			}
+		}
+
+	case reflect.Struct:
+		// Handle time.Time with the default time settings
+		if targetType == timeType {
+			t, err := TimeSettings{}.parse(src)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(t), nil
+		}
+	}
+
+	// Named types such as `type Status string` convert from their base kind.
+	if srcVal.Type().ConvertibleTo(targetType) && srcVal.Kind() == targetType.Kind() {
+		return srcVal.Convert(targetType), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("%w: cannot convert %T to %v", ErrInvalidDataType, src, targetType)
+}
+
+// toInt64 converts an integer, integral float, bool or numeric text to int64.
+func toInt64(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("value %g is not an integer in range", f)
+		}
+		return int64(f), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	if text, ok := textOf(v); ok {
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	}
+	return 0, fmt.Errorf("unsupported source type %v", v.Type())
+}
+
+// toUint64 converts a non-negative integer, integral float, bool or numeric
+// text to uint64.
+func toUint64(v reflect.Value) (uint64, error) {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("negative value %d", v.Int())
+		}
+		return uint64(v.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("value %g is not an unsigned integer in range", f)
+		}
+		return uint64(f), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	if text, ok := textOf(v); ok {
+		return strconv.ParseUint(strings.TrimSpace(text), 10, 64)
+	}
+	return 0, fmt.Errorf("unsupported source type %v", v.Type())
+}
+
+// toFloat64 converts a number or numeric text to float64.
+func toFloat64(v reflect.Value) (float64, error) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	}
+
+	if text, ok := textOf(v); ok {
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	}
+	return 0, fmt.Errorf("unsupported source type %v", v.Type())
+}
+
+// toBool converts a bool, integer or text such as "1", "t" or "false" to bool.
+func toBool(v reflect.Value) (bool, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() != 0, nil
+	}
+
+	if text, ok := textOf(v); ok {
+		return strconv.ParseBool(strings.TrimSpace(text))
+	}
+	return false, fmt.Errorf("unsupported source type %v", v.Type())
+}
+
+// textOf returns the contents of a string or []byte value.
+func textOf(v reflect.Value) (string, bool) {
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes()), true
+	}
+	return "", false
+}
+
+// ContextWithTimeout creates a context with timeout from options.
+func ContextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// IsContextError checks if an error is a context error (deadline or cancellation).
+func IsContextError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// ShouldRetry checks if an operation should be retried based on the error.
+func ShouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Retry on context errors (except cancellation)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Retry on connection errors
+	errStr := strings.ToLower(errorText(err))
+	retryKeywords := []string{
+		"connection",
+		"timeout",
+		"deadline",
+		"busy",
+		"locked",
+		"temporary",
+		"retry",
+	}
+
+	for _, keyword := range retryKeywords {
+		if strings.Contains(errStr, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BuildInsertQuery builds an INSERT query string.
+func BuildInsertQuery(table string, data map[string]any) (string, []any) {
+	columns := make([]string, 0, len(data))
+	placeholders := make([]string, 0, len(data))
+	args := make([]any, 0, len(data))
+
+	for column, value := range data {
+		columns = append(columns, escapeIdentifier(column))
+		placeholders = append(placeholders, "?")
+		args = append(args, value)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
 		escapeIdentifier(table),
-		strings.Join(setClauses, ", "),
-		strings.Join(whereClauses, " AND "),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
 	)
 
 	return query, args
 }
 
-// BuildUpdateQueryWithDriver builds an UPDATE query string with driver-specific escaping.
-func BuildUpdateQueryWithDriver(driver Driver, table string, data, where map[string]any) (string, []any, error) {
+// BuildInsertQueryWithDriver builds an INSERT query string with driver-specific escaping.
+func BuildInsertQueryWithDriver(driver Driver, table string, data map[string]any) (string, []any, error) {
 	escapedTable, err := EscapeTableName(driver, table)
 	if err != nil {
 		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
 	}
 
+	columns, placeholders, args, err := buildInsertDataWithDriver(driver, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		escapedTable,
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+
+	return Rebind(driver, query), args, nil
+}
+
+// BuildInsertManyQueryWithDriver builds a single multi-row INSERT query with
+// driver-specific escaping. Columns are emitted in sorted order and every row
+// must have the same set of columns. No chunking is performed, so more rows
+// than the dialect's InsertRowLimiter allows are an error; see DB.InsertMany.
+func BuildInsertManyQueryWithDriver(driver Driver, table string, rows []Map) (string, []any, error) {
+	if len(rows) == 0 {
+		return "", nil, fmt.Errorf("%w: no rows to insert", ErrInvalidArguments)
+	}
+
+	if limit := maxInsertRows(driver); limit > 0 && len(rows) > limit {
+		return "", nil, fmt.Errorf("%w: %d rows exceed the limit of %d rows per INSERT for %s", ErrInvalidArguments, len(rows), limit, driver)
+	}
+
+	columns, err := insertManyColumns(rows)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return buildInsertManyWithDriver(driver, table, columns, rows)
+}
+
+// BuildUpsertQueryWithDriver builds an insert-or-update query with driver-specific syntax:
+// INSERT ... ON DUPLICATE KEY UPDATE for MySQL,
+// INSERT ... ON CONFLICT (...) DO UPDATE SET ... = EXCLUDED.... for PostgreSQL and SQLite,
+// and MERGE for SQL Server.
+// conflictColumns name the unique key to match; MySQL ignores them and matches
+// on any unique key. If updateColumns is empty, every inserted column that is
+// not a conflict column is updated.
+func BuildUpsertQueryWithDriver(driver Driver, table string, data map[string]any, conflictColumns, updateColumns []string) (string, []any, error) {
+	return buildUpsertWithDriver(driver, table, data, conflictColumns, updateColumns, false)
+}
+
+// BuildInsertIgnoreQueryWithDriver builds an insert that silently skips rows
+// conflicting with an existing unique key: INSERT IGNORE for MySQL,
+// INSERT ... ON CONFLICT DO NOTHING for PostgreSQL and SQLite, and a MERGE
+// without WHEN MATCHED for SQL Server.
+// conflictColumns are ignored by MySQL, optional for PostgreSQL and SQLite,
+// and required by SQL Server.
+func BuildInsertIgnoreQueryWithDriver(driver Driver, table string, data map[string]any, conflictColumns []string) (string, []any, error) {
+	return buildUpsertWithDriver(driver, table, data, conflictColumns, nil, true)
+}
+
+// buildUpsertWithDriver builds the INSERT with its conflict clause.
+func buildUpsertWithDriver(driver Driver, table string, data map[string]any, conflictColumns, updateColumns []string, doNothing bool) (string, []any, error) {
+	if len(data) == 0 {
+		return "", nil, fmt.Errorf("%w: no data to insert", ErrInvalidArguments)
+	}
+
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", nil, err
+	}
+
+	escapedTable, err := EscapeTableName(driver, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
+	}
+
+	columns := Map(data).Keys()
+	sort.Strings(columns)
+
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		if _, ok := data[column].(Expression); ok {
+			return "", nil, fmt.Errorf("%w: expression values are not supported in upserts (column %q)", ErrInvalidArguments, column)
+		}
+		args[i] = data[column]
+	}
+
+	if doNothing {
+		updateColumns = nil
+	} else if len(updateColumns) == 0 {
+		for _, column := range columns {
+			if !slices.Contains(conflictColumns, column) {
+				updateColumns = append(updateColumns, column)
+			}
+		}
+		if len(updateColumns) == 0 {
+			return "", nil, fmt.Errorf("%w: no columns to update on conflict", ErrInvalidArguments)
+		}
+	}
+
+	escapedColumns, err := escapeColumnsWithDriver(driver, columns)
+	if err != nil {
+		return "", nil, err
+	}
+	escapedConflict, err := escapeColumnsWithDriver(driver, conflictColumns)
+	if err != nil {
+		return "", nil, err
+	}
+	escapedUpdate, err := escapeColumnsWithDriver(driver, updateColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query, err := dialect.Upsert(escapedTable, escapedColumns, escapedConflict, escapedUpdate)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return Rebind(driver, query), args, nil
+}
+
+// BuildUpdateQuery builds an UPDATE query string.
+func BuildUpdateQuery(table string, data, where map[string]any) (string, []any) {
 	setClauses := make([]string, 0, len(data))
 	args := make([]any, 0, len(data))
 
 	for column, value := range data {
-		escapedCol, err := EscapeColumnName(driver, column)
-		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
-		}
-		setClauses = append(setClauses, fmt.Sprintf("%s = ?", escapedCol))
+		setClauses = append(setClauses, fmt.Sprintf("%s = ?", escapeIdentifier(column)))
 		args = append(args, value)
 	}
 
 	whereClauses := make([]string, 0, len(where))
 	for column, value := range where {
-		escapedCol, err := EscapeColumnName(driver, column)
-		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
-		}
-		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", escapedCol))
+		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", escapeIdentifier(column)))
 		args = append(args, value)
 	}
 
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
-		escapedTable,
+		escapeIdentifier(table),
 		strings.Join(setClauses, ", "),
 		strings.Join(whereClauses, " AND "),
 	)
 
-	return query, args, nil
+	return query, args
+}
+
+// BuildUpdateQueryWithDriver builds an UPDATE query string with driver-specific escaping.
+// The where argument accepts a Map (implicit AND of equalities) or any Condition
+// and must not be empty.
+func BuildUpdateQueryWithDriver(driver Driver, table string, data map[string]any, where Condition) (string, []any, error) {
+	escapedTable, err := EscapeTableName(driver, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
+	}
+
+	setClause, args, err := buildSetClauseWithDriver(driver, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	whereClause, whereArgs, err := buildWhereClauseWithDriver(driver, where)
+	if err != nil {
+		return "", nil, fmt.Errorf("build where clause: %w", err)
+	}
+	if whereClause == "" {
+		return "", nil, fmt.Errorf("%w: WHERE clause is required for UPDATE", ErrInvalidArguments)
+	}
+	args = append(args, whereArgs...)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		escapedTable,
+		setClause,
+		whereClause,
+	)
+
+	return Rebind(driver, query), args, nil
 }
 
 // BuildDeleteQuery builds a DELETE query string.
@@ -716,30 +1593,28 @@ func BuildDeleteQuery(table string, where map[string]any) (string, []any) {
 }
 
 // BuildDeleteQueryWithDriver builds a DELETE query string with driver-specific escaping.
-func BuildDeleteQueryWithDriver(driver Driver, table string, where map[string]any) (string, []any, error) {
+// The where argument accepts a Map (implicit AND of equalities) or any Condition
+// and must not be empty.
+func BuildDeleteQueryWithDriver(driver Driver, table string, where Condition) (string, []any, error) {
 	escapedTable, err := EscapeTableName(driver, table)
 	if err != nil {
 		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
 	}
 
-	whereClauses := make([]string, 0, len(where))
-	args := make([]any, 0, len(where))
-
-	for column, value := range where {
-		escapedCol, err := EscapeColumnName(driver, column)
-		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
-		}
-		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", escapedCol))
-		args = append(args, value)
+	whereClause, args, err := buildWhereClauseWithDriver(driver, where)
+	if err != nil {
+		return "", nil, fmt.Errorf("build where clause: %w", err)
+	}
+	if whereClause == "" {
+		return "", nil, fmt.Errorf("%w: WHERE clause is required for DELETE", ErrInvalidArguments)
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s",
 		escapedTable,
-		strings.Join(whereClauses, " AND "),
+		whereClause,
 	)
 
-	return query, args, nil
+	return Rebind(driver, query), args, nil
 }
 
 // BuildSelectQuery builds a SELECT query string.
@@ -770,46 +1645,34 @@ func BuildSelectQuery(table string, columns []string, where map[string]any) (str
 }
 
 // BuildSelectQueryWithDriver builds a SELECT query string with driver-specific escaping.
-func BuildSelectQueryWithDriver(driver Driver, table string, columns []string, where map[string]any) (string, []any, error) {
+// The where argument accepts a Map (implicit AND of equalities), any Condition, or nil.
+func BuildSelectQueryWithDriver(driver Driver, table string, columns []string, where Condition) (string, []any, error) {
 	escapedTable, err := EscapeTableName(driver, table)
 	if err != nil {
 		return "", nil, fmt.Errorf("escape table name %q: %w", table, err)
 	}
 
-	columnList := "*"
-	if len(columns) > 0 {
-		escaped := make([]string, len(columns))
-		for i, col := range columns {
-			escapedCol, err := EscapeColumnName(driver, col)
-			if err != nil {
-				return "", nil, fmt.Errorf("escape column %q: %w", col, err)
-			}
-			escaped[i] = escapedCol
-		}
-		columnList = strings.Join(escaped, ", ")
+	columnList, err := buildColumnListWithDriver(driver, columns)
+	if err != nil {
+		return "", nil, err
 	}
 
-	whereClauses := make([]string, 0, len(where))
-	args := make([]any, 0, len(where))
-
-	for column, value := range where {
-		escapedCol, err := EscapeColumnName(driver, column)
-		if err != nil {
-			return "", nil, fmt.Errorf("escape column %q: %w", column, err)
-		}
-		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", escapedCol))
-		args = append(args, value)
+	whereClause, args, err := buildWhereClauseWithDriver(driver, where)
+	if err != nil {
+		return "", nil, fmt.Errorf("build where clause: %w", err)
 	}
 
 	query := fmt.Sprintf("SELECT %s FROM %s", columnList, escapedTable)
-	if len(whereClauses) > 0 {
-		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	if whereClause != "" {
+		query += " WHERE " + whereClause
 	}
 
-	return query, args, nil
+	return Rebind(driver, query), args, nil
 }
 
-// Paginate adds pagination to a query.
+// Paginate adds pagination to a query. An existing top-level LIMIT/OFFSET
+// is replaced, and the clause is placed before a trailing locking clause or
+// semicolon.
 func Paginate(query string, page, pageSize int) string {
 	if page < 1 {
 		page = 1
@@ -819,10 +1682,11 @@ func Paginate(query string, page, pageSize int) string {
 	}
 
 	offset := (page - 1) * pageSize
-	return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pageSize, offset)
+	return paginate("", query, fmt.Sprintf("LIMIT %d OFFSET %d", pageSize, offset))
 }
 
 // PaginateWithDriver adds pagination to a query with driver-specific syntax.
+// See Paginate.
 func PaginateWithDriver(driver Driver, query string, page, pageSize int) (string, error) {
 	if page < 1 {
 		page = 1
@@ -833,18 +1697,54 @@ func PaginateWithDriver(driver Driver, query string, page, pageSize int) (string
 
 	offset := (page - 1) * pageSize
 
-	switch driver {
-	case MySQL:
-		return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pageSize, offset), nil
-	case PostgreSQL:
-		return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pageSize, offset), nil
-	case SQLite:
-		return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pageSize, offset), nil
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", err
+	}
+
+	if dialect.Supports(FeaturePaginationRequiresOrderBy) &&
+		parseStatement(lexRulesOf(dialect), query).find(0, "ORDER", "BY") < 0 {
+		return "", fmt.Errorf("%w: pagination for %s requires an ORDER BY clause", ErrInvalidQuery, driver)
+	}
+	return paginate(driver, query, dialect.Limit(pageSize, offset)), nil
+}
+
+// paginate replaces the top-level LIMIT/OFFSET/FETCH clauses of a query with
+// clause, keeping any locking clause or semicolon that follows them.
+func paginate(driver Driver, query, clause string) string {
+	stmt := parseStatement(lexRulesFor(driver), query)
+
+	stop := firstIndex(stmt.find(0, "FOR"), stmt.find(0, "LOCK", "IN"), stmt.semicolon(0))
+	cut := firstIndex(stmt.find(0, "LIMIT"), stmt.find(0, "OFFSET"), stmt.find(0, "FETCH"), stop)
+	head := strings.TrimRightFunc(query[:stmt.offset(cut)], unicode.IsSpace)
+	rest := query[stmt.offset(stop):]
+
+	switch {
+	case rest == "":
+		return head + " " + clause
+	case rest[0] == ';':
+		return head + " " + clause + rest
 	default:
-		return "", fmt.Errorf("%w: %s", ErrDriverNotSupported, driver)
+		return head + " " + clause + " " + rest
 	}
 }
 
+// ReturningWithDriver adds a RETURNING clause (OUTPUT for SQL Server) to an
+// INSERT, UPDATE or DELETE query. With no columns, all columns are returned. Drivers whose
+// dialect lacks FeatureReturning yield ErrDriverNotSupported.
+func ReturningWithDriver(driver Driver, query string, columns ...string) (string, error) {
+	dialect := lookupDialect(driver)
+	if dialect == nil || !dialect.Supports(FeatureReturning) {
+		return "", fmt.Errorf("%w: RETURNING is not available for %s", ErrDriverNotSupported, driver)
+	}
+
+	escaped, err := escapeColumnsWithDriver(driver, columns)
+	if err != nil {
+		return "", err
+	}
+	return dialect.Returning(query, escaped)
+}
+
 // OrderBy adds ORDER BY clause to a query.
 func OrderBy(query string, orderBy string, desc ...bool) string {
 	direction := "ASC"
@@ -871,29 +1771,99 @@ func Having(query string, having string) string {
 	return fmt.Sprintf("%s HAVING %s", query, having)
 }
 
-// Distinct adds DISTINCT keyword to a query.
+// Distinct adds DISTINCT keyword to a query. Queries that are not a plain
+// SELECT are returned unchanged.
 func Distinct(query string) string {
-	// Simple implementation - assumes query starts with SELECT
-	return strings.Replace(query, "SELECT", "SELECT DISTINCT", 1)
+	result, err := DistinctWithDriver("", query)
+	if err != nil {
+		return query
+	}
+	return result
+}
+
+// DistinctWithDriver makes the top-level SELECT of a query a SELECT DISTINCT,
+// replacing an explicit ALL. The query is tokenized with the driver's quoting
+// rules, so leading comments and keywords inside literals are handled.
+func DistinctWithDriver(driver Driver, query string) (string, error) {
+	stmt := parseStatement(lexRulesFor(driver), query)
+
+	sel := stmt.selectStart()
+	if sel < 0 {
+		return "", fmt.Errorf("%w: DISTINCT requires a SELECT statement", ErrInvalidQuery)
+	}
+
+	next := stmt.next(sel + 1)
+	switch {
+	case next >= 0 && stmt.tokens[next].isWord("DISTINCT"):
+		return query, nil
+	case next >= 0 && stmt.tokens[next].isWord("ALL"):
+		return query[:stmt.tokens[next].start] + "DISTINCT" + query[stmt.tokens[next].end:], nil
+	}
+
+	end := stmt.tokens[sel].end
+	return query[:end] + " DISTINCT" + query[end:], nil
 }
 
-// CountQuery converts a SELECT query to a COUNT query.
+// CountQuery converts a SELECT query to a COUNT query. Queries that cannot be
+// converted are returned unchanged. See CountQueryWithDriver.
 func CountQuery(query string) string {
-	// Find the SELECT ... FROM pattern
-	fromIndex := strings.Index(strings.ToUpper(query), "FROM")
-	if fromIndex == -1 {
+	result, err := CountQueryWithDriver("", query)
+	if err != nil {
 		return query
 	}
+	return result
+}
+
+// CountQueryWithDriver converts a SELECT query into one that counts its rows,
+// dropping top-level ORDER BY, LIMIT, OFFSET and locking clauses. A simple
+// query has its select list replaced by COUNT(*); queries whose row count
+// depends on the select list or grouping (DISTINCT, GROUP BY, HAVING, set
+// operations, CTEs) are wrapped as SELECT COUNT(*) FROM (...) t. For a query
+// with a WITH clause only the final SELECT is wrapped and the WITH clause is
+// kept in front, since SQL Server does not allow a CTE in a derived table.
+//
+// The query is tokenized with the driver's quoting rules, so FROM, ORDER BY
+// and LIMIT inside subqueries, literals, comments or identifiers such as
+// from_date are not mistaken for clauses.
+func CountQueryWithDriver(driver Driver, query string) (string, error) {
+	stmt := parseStatement(lexRulesFor(driver), query)
+
+	start := stmt.next(0)
+	if start >= 0 && stmt.tokens[start].isWord("WITH") {
+		main := stmt.find(start+1, "SELECT")
+		if main < 0 {
+			return "", fmt.Errorf("%w: count query requires a SELECT statement", ErrInvalidQuery)
+		}
+		with := strings.TrimSpace(query[stmt.tokens[start].start:stmt.tokens[main].start])
+		body := strings.TrimSpace(query[stmt.tokens[main].start:stmt.offset(stmt.tail(main))])
+		return with + " SELECT COUNT(*) FROM (" + body + ") t", nil
+	}
+
+	sel := stmt.selectStart()
+	if sel < 0 {
+		return "", fmt.Errorf("%w: count query requires a SELECT statement", ErrInvalidQuery)
+	}
+
+	from := stmt.find(sel+1, "FROM")
+	if from < 0 {
+		return "", fmt.Errorf("%w: count query requires a FROM clause", ErrInvalidQuery)
+	}
 
-	// Keep everything from FROM onward
-	fromPart := query[fromIndex:]
+	end := stmt.tail(from)
+	before := func(i int) bool { return i >= 0 && (end < 0 || i < end) }
 
-	// Remove ORDER BY, LIMIT, OFFSET for count query
-	fromPart = strings.Split(fromPart, "ORDER BY")[0]
-	fromPart = strings.Split(fromPart, "LIMIT")[0]
-	fromPart = strings.Split(fromPart, "OFFSET")[0]
+	next := stmt.next(sel + 1)
+	if stmt.tokens[next].isWord("DISTINCT") ||
+		before(stmt.find(from, "GROUP", "BY")) ||
+		before(stmt.find(from, "HAVING")) ||
+		before(stmt.find(from, "UNION")) ||
+		before(stmt.find(from, "INTERSECT")) ||
+		before(stmt.find(from, "EXCEPT")) {
+		body := strings.TrimSpace(query[stmt.tokens[sel].start:stmt.offset(end)])
+		return "SELECT COUNT(*) FROM (" + body + ") t", nil
+	}
 
-	return fmt.Sprintf("SELECT COUNT(*) %s", fromPart)
+	return "SELECT COUNT(*) " + strings.TrimSpace(query[stmt.tokens[from].start:stmt.offset(end)]), nil
 }
 
 // Placeholders generates a string of SQL placeholders.
@@ -908,7 +1878,54 @@ func Placeholders(count int) string {
 	return strings.Join(placeholders, ", ")
 }
 
+// Rebind converts a query written with '?' placeholders into the placeholder
+// style of the driver's dialect ($1, $2, ... for PostgreSQL). Question
+// marks inside string literals, quoted identifiers and comments are left
+// untouched. Drivers that use '?' natively get the query back unchanged.
+func Rebind(driver Driver, query string) string {
+	dialect := lookupDialect(driver)
+	if dialect == nil || dialect.Placeholder(1) == "?" {
+		return query
+	}
+	rules := lexRulesFor(driver)
+
+	var builder strings.Builder
+	builder.Grow(len(query) + 8)
+
+	n := 0
+	for i := 0; i < len(query); {
+		if end := skipNonCode(rules, query, i); end > i {
+			builder.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		if query[i] == '?' {
+			n++
+			builder.WriteString(dialect.Placeholder(n))
+		} else {
+			builder.WriteByte(query[i])
+		}
+		i++
+	}
+
+	return builder.String()
+}
+
+// skipNonCode returns the index just past the string literal, quoted
+// identifier or comment starting at position i of query, following the
+// given quoting rules. If no such construct starts at i, it returns i.
+func skipNonCode(rules lexRules, query string, i int) int {
+	switch kind, end := scanToken(rules, query, i); kind {
+	case tokenString, tokenIdent, tokenComment:
+		return end
+	}
+	return i
+}
+
 // InClause builds an IN clause with placeholders.
+// A count of zero renders the invalid "IN ()"; use In to expand slice
+// arguments with an error on empty input instead.
 func InClause(column string, count int) string {
 	if count <= 0 {
 		return fmt.Sprintf("%s IN ()", escapeIdentifier(column))
@@ -931,6 +1948,22 @@ func NullSafeEqual(column string) string {
 	return fmt.Sprintf("%s <=> ?", escapeIdentifier(column))
 }
 
+// NullSafeEqualWithDriver builds a null-safe equality comparison of a column
+// with a placeholder using the driver's dialect: <=> for MySQL,
+// IS NOT DISTINCT FROM for PostgreSQL and IS for SQLite.
+func NullSafeEqualWithDriver(driver Driver, column string) (string, error) {
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", err
+	}
+
+	escaped, err := EscapeColumnName(driver, column)
+	if err != nil {
+		return "", fmt.Errorf("escape column %q: %w", column, err)
+	}
+	return dialect.NullSafeEqual(escaped, "?"), nil
+}
+
 // IsNull builds an IS NULL check.
 func IsNull(column string) string {
 	return fmt.Sprintf("%s IS NULL", escapeIdentifier(column))
@@ -970,6 +2003,35 @@ func Concat(columns ...string) string {
 	return fmt.Sprintf("CONCAT(%s)", strings.Join(escaped, ", "))
 }
 
+// ConcatWithDriver builds a string concatenation of columns using the
+// driver's dialect: CONCAT() for MySQL and || for PostgreSQL and SQLite.
+func ConcatWithDriver(driver Driver, columns ...string) (string, error) {
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("%w: no columns to concatenate", ErrInvalidArguments)
+	}
+
+	escaped := make([]string, len(columns))
+	for i, column := range columns {
+		if escaped[i], err = EscapeColumnName(driver, column); err != nil {
+			return "", fmt.Errorf("escape column %q: %w", column, err)
+		}
+	}
+	return dialect.Concat(escaped...), nil
+}
+
+// BoolWithDriver returns the boolean literal of the driver's dialect.
+func BoolWithDriver(driver Driver, value bool) (string, error) {
+	dialect, err := GetDialect(driver)
+	if err != nil {
+		return "", err
+	}
+	return dialect.Bool(value), nil
+}
+
 // CaseBuilder helps build CASE expressions.
 type CaseBuilder struct {
 	caseExpr string